@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAgentHealth() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAgentHealthRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "The name of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"version": {
+				Description: "The Nomad version of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"region": {
+				Description: "The region of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"datacenter": {
+				Description: "The datacenter of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"server": {
+				Description: "Whether the agent runs a server.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"leader": {
+				Description: "Whether the agent is the leader of its region.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"known_servers": {
+				Description: "The addresses of the servers known to the agent.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"healthy": {
+				Description: "Whether the agent reports itself as healthy.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"health_message": {
+				Description: "The reason the agent is unhealthy, if any.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+		},
+	}
+}
+
+func dataSourceAgentHealthRead(d *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	log.Printf("[DEBUG] Reading agent information from Nomad")
+	self, err := client.Agent().Self()
+	if err != nil {
+		return fmt.Errorf("error reading agent information from Nomad: %s", err)
+	}
+
+	server := self.Stats["nomad"]["server"] == "true"
+	knownServers, err := agentKnownServers(client, self, server)
+	if err != nil {
+		return fmt.Errorf("error reading servers known to the agent: %s", err)
+	}
+
+	// The health endpoint returns an error status if the agent is
+	// unhealthy, so errors are reported as such instead of failing.
+	healthy, healthMessage := true, ""
+	health, err := client.Agent().Health()
+	if err != nil {
+		log.Printf("[WARN] agent health check failed: %s", err)
+		healthy, healthMessage = false, err.Error()
+	} else {
+		for _, h := range []*api.AgentHealth{health.Server, health.Client} {
+			if h != nil && !h.Ok {
+				healthy, healthMessage = false, h.Message
+			}
+		}
+	}
+	log.Printf("[DEBUG] Read agent information from Nomad")
+
+	d.SetId(self.Member.Name)
+	d.Set("name", self.Member.Name)
+	d.Set("version", agentVersion(self))
+	d.Set("region", self.Config["Region"])
+	d.Set("datacenter", self.Config["Datacenter"])
+	d.Set("server", server)
+	d.Set("leader", self.Stats["nomad"]["leader"] == "true")
+	d.Set("known_servers", knownServers)
+	d.Set("healthy", healthy)
+	d.Set("health_message", healthMessage)
+
+	return nil
+}
+
+// agentVersion returns the version of the agent, including the prerelease
+// suffix if any.
+func agentVersion(self *api.AgentSelf) string {
+	versionConfig, _ := self.Config["Version"].(map[string]interface{})
+	version, _ := versionConfig["Version"].(string)
+	if prerelease, _ := versionConfig["VersionPrerelease"].(string); prerelease != "" {
+		version = fmt.Sprintf("%s-%s", version, prerelease)
+	}
+	return version
+}
+
+// agentKnownServers returns the sorted addresses of the servers known to the
+// agent: the Raft peers for servers and the servers it's connected to for
+// clients.
+func agentKnownServers(client *api.Client, self *api.AgentSelf, server bool) ([]string, error) {
+	var servers []string
+	if server {
+		peers, err := client.Status().Peers()
+		if err != nil {
+			return nil, err
+		}
+		servers = peers
+	} else if known := self.Stats["client"]["known_servers"]; known != "" {
+		servers = strings.Split(known, ",")
+	}
+
+	sort.Strings(servers)
+	return servers, nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceAgentHealth_Basic(t *testing.T) {
+	resourceName := "data.nomad_agent_health.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceAgentHealthConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "name"),
+					resource.TestCheckResourceAttrSet(resourceName, "version"),
+					resource.TestCheckResourceAttr(resourceName, "region", "global"),
+					resource.TestCheckResourceAttr(resourceName, "server", "true"),
+					resource.TestCheckResourceAttr(resourceName, "leader", "true"),
+					resource.TestCheckResourceAttr(resourceName, "known_servers.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "healthy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAgentVersion(t *testing.T) {
+	cases := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{
+			config:   map[string]interface{}{},
+			expected: "",
+		},
+		{
+			config: map[string]interface{}{
+				"Version": map[string]interface{}{"Version": "1.8.0", "VersionPrerelease": ""},
+			},
+			expected: "1.8.0",
+		},
+		{
+			config: map[string]interface{}{
+				"Version": map[string]interface{}{"Version": "1.8.0", "VersionPrerelease": "beta.1"},
+			},
+			expected: "1.8.0-beta.1",
+		},
+	}
+	for _, tc := range cases {
+		got := agentVersion(&api.AgentSelf{Config: tc.config})
+		if got != tc.expected {
+			t.Errorf("expected version %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+const testDataSourceAgentHealthConfig = `
+data "nomad_agent_health" "test" {}
+`
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -15,6 +16,11 @@ func dataSourceNamespaces() *schema.Resource {
 		Read: namespacesDataSourceRead,
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Description: "The region to query. Defaults to the provider region.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
 			"namespaces": {
 				Type:     schema.TypeList,
 				Elem:     &schema.Schema{Type: schema.TypeString},
@@ -28,7 +34,9 @@ func namespacesDataSourceRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(ProviderConfig).client
 
 	log.Printf("[DEBUG] Reading namespaces from Nomad")
-	resp, _, err := client.Namespaces().List(nil)
+	resp, _, err := client.Namespaces().List(&api.QueryOptions{
+		Region: d.Get("region").(string),
+	})
 	if err != nil {
 		return fmt.Errorf("error reading namespaces from Nomad: %s", err)
 	}
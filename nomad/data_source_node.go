@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceNode() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNodeRead,
+
+		Schema: map[string]*schema.Schema{
+			"node_id": {
+				Description:  "The ID of the node.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"node_id", "name"},
+			},
+			"name": {
+				Description:  "The name of the node.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"node_id", "name"},
+			},
+			"status": {
+				Description: "The status of the node.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"node_class": {
+				Description: "The class of the node.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"node_pool": {
+				Description: "The node pool of the node.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"datacenter": {
+				Description: "The datacenter of the node.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"drain": {
+				Description: "Whether the node is draining.",
+				Type:        schema.TypeBool,
+				Computed:    true,
+			},
+			"eligibility": {
+				Description: "The scheduling eligibility of the node.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"attributes": {
+				Description: "The attributes fingerprinted on the node.",
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+			},
+			"meta": {
+				Description: "The metadata of the node.",
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceNodeRead(d *schema.ResourceData, meta any) error {
+	client := meta.(ProviderConfig).client
+
+	nodeID := d.Get("node_id").(string)
+	if name := d.Get("name").(string); nodeID == "" && name != "" {
+		var err error
+		nodeID, err = nodeIDByName(client, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Reading node %q", nodeID)
+	node, _, err := client.Nodes().Info(nodeID, nil)
+	if err != nil {
+		return fmt.Errorf("error reading node %q: %w", nodeID, err)
+	}
+	log.Printf("[DEBUG] Read node %q", nodeID)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("node_id", node.ID)
+	sw.Set("name", node.Name)
+	sw.Set("status", node.Status)
+	sw.Set("node_class", node.NodeClass)
+	sw.Set("node_pool", node.NodePool)
+	sw.Set("datacenter", node.Datacenter)
+	sw.Set("drain", node.Drain)
+	sw.Set("eligibility", node.SchedulingEligibility)
+	sw.Set("attributes", node.Attributes)
+	sw.Set("meta", node.Meta)
+	if err := sw.Error(); err != nil {
+		return err
+	}
+
+	d.SetId(node.ID)
+	return nil
+}
+
+// nodeIDByName returns the ID of the node with the given name. Node names
+// are not unique, so it's an error for more than one node to match.
+func nodeIDByName(client *api.Client, name string) (string, error) {
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		return "", fmt.Errorf("error listing nodes: %w", err)
+	}
+
+	var ids []string
+	for _, node := range nodes {
+		if node.Name == name {
+			ids = append(ids, node.ID)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("node %q not found", name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("found %d nodes named %q, use node_id instead", len(ids), name)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceNode(t *testing.T) {
+	steps := []resource.TestStep{
+		{
+			Config:      testDataSourceNodeConfig_doesntExist,
+			ExpectError: regexp.MustCompile(`node "doesnt-exist" not found`),
+		},
+		// The node ID and the checks depend on the nodes of the cluster, so
+		// this step is set in PreCheck.
+		{Config: testDataSourceNodeConfig_basic("")},
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck: func() {
+			testAccPreCheck(t)
+
+			client := testProvider.Meta().(ProviderConfig).client
+			nodes, _, err := client.Nodes().List(nil)
+			if err != nil || len(nodes) == 0 {
+				t.Skip("error listing nodes: ", err)
+			}
+			node := nodes[0]
+
+			steps[1] = resource.TestStep{
+				Config: testDataSourceNodeConfig_basic(node.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nomad_node.by_id", "node_id", node.ID),
+					resource.TestCheckResourceAttr("data.nomad_node.by_id", "name", node.Name),
+					resource.TestCheckResourceAttr("data.nomad_node.by_id", "status", node.Status),
+					resource.TestCheckResourceAttr("data.nomad_node.by_id", "datacenter", node.Datacenter),
+					resource.TestCheckResourceAttr("data.nomad_node.by_id", "eligibility", node.SchedulingEligibility),
+					resource.TestCheckResourceAttrSet("data.nomad_node.by_id", "attributes.kernel.name"),
+					resource.TestCheckResourceAttr("data.nomad_node.by_name", "node_id", node.ID),
+				),
+			}
+		},
+		Steps: steps,
+	})
+}
+
+const testDataSourceNodeConfig_doesntExist = `
+data "nomad_node" "doesnt_exist" {
+  name = "doesnt-exist"
+}
+`
+
+func testDataSourceNodeConfig_basic(nodeID string) string {
+	return fmt.Sprintf(`
+data "nomad_node" "by_id" {
+  node_id = %q
+}
+
+data "nomad_node" "by_name" {
+  name = data.nomad_node.by_id.name
+}
+`, nodeID)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -15,6 +16,11 @@ func dataSourceRegions() *schema.Resource {
 		Read: regionsDataSourceRead,
 
 		Schema: map[string]*schema.Schema{
+			"region": {
+				Description: "The region to query. Defaults to the provider region.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
 			"regions": {
 				Type:     schema.TypeList,
 				Elem:     &schema.Schema{Type: schema.TypeString},
@@ -28,7 +34,12 @@ func regionsDataSourceRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(ProviderConfig).client
 
 	log.Printf("[DEBUG] Reading regions from Nomad")
-	resp, err := client.Regions().List()
+	// Regions().List() doesn't accept query options, so use the raw client to
+	// be able to target a specific region.
+	var resp []string
+	_, err := client.Raw().Query("/v1/regions", &resp, &api.QueryOptions{
+		Region: d.Get("region").(string),
+	})
 	if err != nil {
 		return fmt.Errorf("error reading regions from Nomad: %s", err)
 	}
@@ -21,6 +21,10 @@ func TestDataSourceRegions(t *testing.T) {
 				Config: testDataSourceRegions_config,
 				Check:  testDataSourceRegions_check,
 			},
+			{
+				Config: testDataSourceRegions_regionConfig,
+				Check:  testDataSourceRegions_check,
+			},
 		},
 	})
 }
@@ -32,6 +36,14 @@ data "nomad_regions" "test" {
 
 `
 
+var testDataSourceRegions_regionConfig = `
+
+data "nomad_regions" "test" {
+  region = "global"
+}
+
+`
+
 func testDataSourceRegions_check(s *terraform.State) error {
 	resourceState := s.Modules[0].Resources["data.nomad_regions.test"]
 	if resourceState == nil {
@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceService() *schema.Resource {
+	return &schema.Resource{
+		Read: serviceDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "The name of the service.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"namespace": {
+				Description: "The namespace the service is registered in.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"instances": serviceInstancesSchema(),
+		},
+	}
+}
+
+// serviceInstancesSchema returns the schema of the instances attribute shared
+// by the service data sources.
+func serviceInstancesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The registered instances of the service.",
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Description: "The service registration ID.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"address": {
+					Description: "The address of the instance.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"port": {
+					Description: "The port of the instance.",
+					Type:        schema.TypeInt,
+					Computed:    true,
+				},
+				"node_id": {
+					Description: "The ID of the node running the instance.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"alloc_id": {
+					Description: "The ID of the allocation that registered the instance.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"job_id": {
+					Description: "The ID of the job that registered the instance.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"datacenter": {
+					Description: "The datacenter of the instance.",
+					Type:        schema.TypeString,
+					Computed:    true,
+				},
+				"tags": {
+					Description: "The tags of the instance.",
+					Type:        schema.TypeList,
+					Computed:    true,
+					Elem:        &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func serviceDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	name := d.Get("name").(string)
+	ns := d.Get("namespace").(string)
+
+	log.Printf("[DEBUG] Reading service %q from Nomad", name)
+	resp, _, err := client.Services().Get(name, &api.QueryOptions{
+		Namespace: ns,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading service %q from Nomad: %s", name, err)
+	}
+	if len(resp) == 0 {
+		return fmt.Errorf("no instances of service %q found in namespace %q", name, ns)
+	}
+
+	log.Printf("[DEBUG] Read service %q from Nomad", name)
+
+	d.SetId(fmt.Sprintf("%s/%s", ns, name))
+	if err := d.Set("instances", serviceInstancesRaw(resp)); err != nil {
+		return fmt.Errorf("failed to set instances: %v", err)
+	}
+
+	return nil
+}
+
+// serviceInstancesRaw flattens the service registrations returned by Nomad
+// into the format of the instances attribute.
+func serviceInstancesRaw(regs []*api.ServiceRegistration) []interface{} {
+	instances := make([]interface{}, 0, len(regs))
+	for _, s := range regs {
+		instances = append(instances, map[string]interface{}{
+			"id":         s.ID,
+			"address":    s.Address,
+			"port":       s.Port,
+			"node_id":    s.NodeID,
+			"alloc_id":   s.AllocID,
+			"job_id":     s.JobID,
+			"datacenter": s.Datacenter,
+			"tags":       s.Tags,
+		})
+	}
+	return instances
+}
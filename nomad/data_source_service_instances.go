@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceServiceInstances() *schema.Resource {
+	return &schema.Resource{
+		Read: serviceInstancesDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"service_name": {
+				Description: "The name of the service.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"namespace": {
+				Description: "The namespace the service is registered in.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"tag": {
+				Description: "Only return the instances that have this tag.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"datacenter": {
+				Description: "Only return the instances running in this datacenter.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"instances": serviceInstancesSchema(),
+		},
+	}
+}
+
+func serviceInstancesDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	name := d.Get("service_name").(string)
+	ns := d.Get("namespace").(string)
+
+	log.Printf("[DEBUG] Reading instances of service %q from Nomad", name)
+	resp, _, err := client.Services().Get(name, &api.QueryOptions{
+		Namespace: ns,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading service %q from Nomad: %s", name, err)
+	}
+
+	// Nomad removes a service from its catalog once its last instance is
+	// deregistered so an empty response means the service does not exist.
+	if len(resp) == 0 {
+		return fmt.Errorf("service %q not found in namespace %q", name, ns)
+	}
+
+	// The service exists but none of its instances may match the filters,
+	// this is not an error and instances is set to an empty list.
+	regs := filterServiceInstances(resp, d.Get("tag").(string), d.Get("datacenter").(string))
+	log.Printf("[DEBUG] Read %d of the %d instances of service %q from Nomad", len(regs), len(resp), name)
+
+	d.SetId(fmt.Sprintf("%s/%s", ns, name))
+	if err := d.Set("instances", serviceInstancesRaw(regs)); err != nil {
+		return fmt.Errorf("failed to set instances: %v", err)
+	}
+
+	return nil
+}
+
+// filterServiceInstances returns the service registrations that have the
+// given tag and run in the given datacenter. Empty filters match all the
+// registrations.
+func filterServiceInstances(regs []*api.ServiceRegistration, tag, datacenter string) []*api.ServiceRegistration {
+	filtered := make([]*api.ServiceRegistration, 0, len(regs))
+	for _, s := range regs {
+		if tag != "" && !slices.Contains(s.Tags, tag) {
+			continue
+		}
+		if datacenter != "" && s.Datacenter != datacenter {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered
+}
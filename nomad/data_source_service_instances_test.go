@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/shoenig/test/must"
+)
+
+func TestFilterServiceInstances(t *testing.T) {
+	regs := []*api.ServiceRegistration{
+		{ID: "a", Datacenter: "dc1", Tags: []string{"web", "blue"}},
+		{ID: "b", Datacenter: "dc2", Tags: []string{"web", "green"}},
+		{ID: "c", Datacenter: "dc1"},
+	}
+
+	ids := func(regs []*api.ServiceRegistration) []string {
+		res := []string{}
+		for _, r := range regs {
+			res = append(res, r.ID)
+		}
+		return res
+	}
+
+	must.Eq(t, []string{"a", "b", "c"}, ids(filterServiceInstances(regs, "", "")))
+	must.Eq(t, []string{"a", "b"}, ids(filterServiceInstances(regs, "web", "")))
+	must.Eq(t, []string{"a", "c"}, ids(filterServiceInstances(regs, "", "dc1")))
+	must.Eq(t, []string{"b"}, ids(filterServiceInstances(regs, "green", "dc2")))
+	must.Eq(t, []string{}, ids(filterServiceInstances(regs, "green", "dc1")))
+}
+
+func TestDataSourceServiceInstances_basic(t *testing.T) {
+	dataSourceName := "data.nomad_service_instances.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.3.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceServicesJobConfig,
+			},
+			{
+				Config: testDataSourceServicesJobConfig + `
+data "nomad_service_instances" "test" {
+  service_name = "tf-ds-service"
+  tag          = "web"
+  datacenter   = "dc1"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "default/tf-ds-service"),
+					resource.TestCheckResourceAttr(dataSourceName, "instances.#", "1"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.address"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.port"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.node_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.alloc_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "instances.0.tags.#", "2"),
+				),
+			},
+			{
+				Config: testDataSourceServicesJobConfig + `
+data "nomad_service_instances" "test" {
+  service_name = "tf-ds-service"
+  tag          = "green"
+}
+`,
+				Check: resource.TestCheckResourceAttr(dataSourceName, "instances.#", "0"),
+			},
+			{
+				Config: `
+data "nomad_service_instances" "test" {
+  service_name = "tf-ds-service-missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`service "tf-ds-service-missing" not found`),
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("tf-ds-service"),
+	})
+}
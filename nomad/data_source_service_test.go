@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceService_basic(t *testing.T) {
+	dataSourceName := "data.nomad_service.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.3.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceServicesJobConfig,
+			},
+			{
+				Config: testDataSourceServicesJobConfig + `
+data "nomad_service" "test" {
+  name = "tf-ds-service"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "default/tf-ds-service"),
+					resource.TestCheckResourceAttr(dataSourceName, "instances.#", "1"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.address"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.port"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.node_id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "instances.0.alloc_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "instances.0.job_id", "tf-ds-service"),
+				),
+			},
+			{
+				Config: `
+data "nomad_service" "test" {
+  name = "tf-ds-service-missing"
+}
+`,
+				ExpectError: regexp.MustCompile(`no instances of service "tf-ds-service-missing"`),
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("tf-ds-service"),
+	})
+}
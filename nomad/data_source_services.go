@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceServices() *schema.Resource {
+	return &schema.Resource{
+		Read: servicesDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Description: "Namespace to query. Use \"*\" to list services in all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"prefix": {
+				Description: "Service name prefix used to filter services.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"tags": {
+				Description: "Only return services that have all of these tags.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"services": {
+				Description: "The list of services that match the search criteria.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Description: "The service name.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"namespace": {
+							Description: "The namespace the service is registered in.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"tags": {
+							Description: "The tags of the service.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func servicesDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	prefix := d.Get("prefix").(string)
+	var tags []string
+	for _, t := range d.Get("tags").(*schema.Set).List() {
+		tags = append(tags, t.(string))
+	}
+
+	log.Printf("[DEBUG] Reading services from Nomad")
+	resp, _, err := client.Services().List(&api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	})
+	if err != nil {
+		return fmt.Errorf("error reading services from Nomad: %s", err)
+	}
+
+	services := make([]interface{}, 0)
+	for _, ns := range resp {
+		for _, s := range ns.Services {
+			if !strings.HasPrefix(s.ServiceName, prefix) {
+				continue
+			}
+			if !serviceHasTags(s.Tags, tags) {
+				continue
+			}
+
+			serviceTags := make([]string, len(s.Tags))
+			copy(serviceTags, s.Tags)
+			sort.Strings(serviceTags)
+
+			services = append(services, map[string]interface{}{
+				"name":      s.ServiceName,
+				"namespace": ns.Namespace,
+				"tags":      serviceTags,
+			})
+		}
+	}
+	log.Printf("[DEBUG] Read services from Nomad")
+
+	d.SetId(resource.UniqueId())
+	if err := d.Set("services", services); err != nil {
+		return fmt.Errorf("failed to set services: %v", err)
+	}
+
+	return nil
+}
+
+// serviceHasTags returns true if have contains every tag in want.
+func serviceHasTags(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if h == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestServiceHasTags(t *testing.T) {
+	cases := []struct {
+		have, want []string
+		expected   bool
+	}{
+		{nil, nil, true},
+		{[]string{"a", "b"}, nil, true},
+		{[]string{"a", "b"}, []string{"b"}, true},
+		{[]string{"a", "b"}, []string{"a", "b"}, true},
+		{[]string{"a"}, []string{"a", "b"}, false},
+		{nil, []string{"a"}, false},
+	}
+	for _, c := range cases {
+		if got := serviceHasTags(c.have, c.want); got != c.expected {
+			t.Errorf("serviceHasTags(%v, %v) = %v, expected %v", c.have, c.want, got, c.expected)
+		}
+	}
+}
+
+func TestDataSourceServices_basic(t *testing.T) {
+	dataSourceName := "data.nomad_services.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.3.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceServicesJobConfig,
+			},
+			{
+				Config: testDataSourceServicesJobConfig + `
+data "nomad_services" "test" {
+  prefix = "tf-ds-service"
+  tags   = ["blue"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "services.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "services.0.name", "tf-ds-service"),
+					resource.TestCheckResourceAttr(dataSourceName, "services.0.namespace", "default"),
+					resource.TestCheckResourceAttr(dataSourceName, "services.0.tags.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "services.0.tags.0", "blue"),
+					resource.TestCheckResourceAttr(dataSourceName, "services.0.tags.1", "web"),
+				),
+			},
+			{
+				Config: testDataSourceServicesJobConfig + `
+data "nomad_services" "test" {
+  prefix = "tf-ds-service"
+  tags   = ["green"]
+}
+`,
+				Check: resource.TestCheckResourceAttr(dataSourceName, "services.#", "0"),
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("tf-ds-service"),
+	})
+}
+
+const testDataSourceServicesJobConfig = `
+resource "nomad_job" "test" {
+  detach = false
+
+  jobspec = <<EOT
+job "tf-ds-service" {
+  datacenters = ["dc1"]
+  group "foo" {
+    network {
+      port "http" {}
+    }
+
+    service {
+      name     = "tf-ds-service"
+      port     = "http"
+      provider = "nomad"
+      tags     = ["web", "blue"]
+    }
+
+    task "foo" {
+      driver = "raw_exec"
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+    }
+  }
+}
+EOT
+}
+`
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceVariables() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVariablesRead,
+
+		Schema: map[string]*schema.Schema{
+			"prefix": {
+				Description: "Specifies a string to filter variables based on a path prefix.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"namespace": {
+				Description: "Specifies the namespace to list variables from. Use \"*\" to list variables from all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     api.DefaultNamespace,
+			},
+			"variables": {
+				Description: "List of variables returned",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"namespace": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"create_index": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"modify_index": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"create_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"modify_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVariablesRead(d *schema.ResourceData, meta any) error {
+	client := meta.(ProviderConfig).client
+
+	prefix := d.Get("prefix").(string)
+	namespace := d.Get("namespace").(string)
+
+	log.Printf("[DEBUG] Reading variable list")
+	resp, err := listVariables(client, &api.QueryOptions{
+		Namespace: namespace,
+		Prefix:    prefix,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading variables: %w", err)
+	}
+
+	vars := make([]map[string]any, len(resp))
+	for i, v := range resp {
+		vars[i] = map[string]any{
+			"path":         v.Path,
+			"namespace":    v.Namespace,
+			"create_index": int(v.CreateIndex),
+			"modify_index": int(v.ModifyIndex),
+			"create_time":  int(v.CreateTime),
+			"modify_time":  int(v.ModifyTime),
+		}
+	}
+	log.Printf("[DEBUG] Read %d variables", len(vars))
+
+	d.SetId(strconv.Itoa(schema.HashString(namespace + "/" + prefix)))
+	return d.Set("variables", vars)
+}
+
+// listVariables lists the variables matching opts, following the pagination
+// token returned by Nomad until all the pages are read.
+func listVariables(client *api.Client, opts *api.QueryOptions) ([]*api.VariableMetadata, error) {
+	q := *opts
+	var vars []*api.VariableMetadata
+	for {
+		page, meta, err := client.Variables().List(&q)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, page...)
+
+		if meta == nil || meta.NextToken == "" {
+			return vars, nil
+		}
+		q.NextToken = meta.NextToken
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestListVariables(t *testing.T) {
+	const total = 5
+	vars := make([]*api.VariableMetadata, total)
+	for i := range vars {
+		vars[i] = &api.VariableMetadata{
+			Namespace: "prod",
+			Path:      fmt.Sprintf("app/%d", i),
+		}
+	}
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query := r.URL.Query()
+		if ns := query.Get("namespace"); ns != "prod" {
+			t.Errorf("unexpected namespace %q", ns)
+		}
+		if prefix := query.Get("prefix"); prefix != "app/" {
+			t.Errorf("unexpected prefix %q", prefix)
+		}
+
+		// Return pages of two variables, using the index of the next
+		// variable as the pagination token.
+		start := 0
+		if token := query.Get("next_token"); token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := min(start+2, total)
+		w.Header().Set("X-Nomad-Index", "100")
+		w.Header().Set("X-Nomad-LastContact", "0")
+		w.Header().Set("X-Nomad-KnownLeader", "true")
+		if end < total {
+			w.Header().Set("X-Nomad-NextToken", strconv.Itoa(end))
+		}
+		json.NewEncoder(w).Encode(vars[start:end])
+	}))
+	defer srv.Close()
+
+	conf := api.DefaultConfig()
+	conf.Address = srv.URL
+	client, err := api.NewClient(conf)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	got, err := listVariables(client, &api.QueryOptions{
+		Namespace: "prod",
+		Prefix:    "app/",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+	if len(got) != total {
+		t.Fatalf("expected %d variables, got %d", total, len(got))
+	}
+	for i, v := range got {
+		if v.Path != vars[i].Path {
+			t.Fatalf("expected variable %d to be %q, got %q", i, vars[i].Path, v.Path)
+		}
+	}
+}
+
+func TestDataSourceVariables(t *testing.T) {
+	prefix := acctest.RandomWithPrefix("tf-nomad-test")
+	dataSourceName := "data.nomad_variables.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.4.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testDataSourceVariables_config(prefix),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "variables.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "variables.0.path", prefix+"/0"),
+					resource.TestCheckResourceAttr(dataSourceName, "variables.0.namespace", api.DefaultNamespace),
+					resource.TestCheckResourceAttr(dataSourceName, "variables.2.path", prefix+"/2"),
+				),
+			},
+		},
+	})
+}
+
+func testDataSourceVariables_config(prefix string) string {
+	return fmt.Sprintf(`
+resource "nomad_variable" "test" {
+  count = 3
+  path  = "%s/${count.index}"
+  items = {
+    key = "value"
+  }
+}
+
+data "nomad_variables" "test" {
+  prefix = "%s/"
+
+  depends_on = [nomad_variable.test]
+}
+`, prefix, prefix)
+}
@@ -100,6 +100,11 @@ func dataSourceJob() *schema.Resource {
 				Computed:    true,
 				Type:        schema.TypeString,
 			},
+			"dispatched": {
+				Description: "Job Dispatched",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
 			"task_groups": taskGroupSchema(),
 			"stable": {
 				Description: "Job Stable",
@@ -204,6 +209,7 @@ func dataSourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("stop", job.Stop)
 	d.Set("priority", job.Priority)
 	d.Set("parent_id", job.ParentID)
+	d.Set("dispatched", job.Dispatched)
 	d.Set("task_groups", jobTaskGroupsRaw(job.TaskGroups))
 	d.Set("stable", job.Stable)
 	d.Set("all_at_once", job.AllAtOnce)
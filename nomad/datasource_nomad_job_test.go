@@ -32,6 +32,10 @@ func TestAccDataSourceNomadJob_Basic(t *testing.T) {
 						"data.nomad_job.test-job", "priority", "50"),
 					resource.TestCheckResourceAttr(
 						"data.nomad_job.test-job", "namespace", "default"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "dispatched", "false"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "parent_id", ""),
 				),
 			},
 		},
@@ -4,16 +4,23 @@
 package nomad
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/vault/api/cliconfig"
 )
 
@@ -22,6 +29,13 @@ type ProviderConfig struct {
 	vaultToken  *string
 	consulToken *string
 	config      *api.Config
+
+	defaultConsulNamespace  string
+	defaultConsulPartition  string
+	allowedConsulPartitions []string
+	defaultVaultNamespace   string
+	defaultJobPriority      int
+	trackJobSubmissions     bool
 }
 
 func Provider() *schema.Provider {
@@ -97,6 +111,46 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VAULT_TOKEN", ""),
 				Description: "Vault token if policies are specified in the job file.",
 			},
+			"default_consul_namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Consul namespace applied to the consul blocks of jobs that don't set one.",
+			},
+			"default_consul_partition": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Consul admin partition applied to the consul blocks of jobs that don't set one.",
+			},
+			"allowed_consul_partitions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "If set, the Consul admin partitions jobs are allowed to use.",
+			},
+			"default_vault_namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Vault namespace applied to the vault blocks of jobs that don't set one.",
+			},
+			"default_job_priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Priority applied to jobs that don't set one. Defaults to 0, which uses the Nomad default.",
+			},
+			"track_job_submissions": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "If false, nomad_job resources don't read back the jobspec submitted to Nomad unless they set track_submission.",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of requests sent to the Nomad API at the same time. Defaults to 0, which means no limit.",
+			},
 			"secret_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -135,54 +189,69 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("NOMAD_SKIP_VERIFY", false),
 				Description: "Skip TLS verification on client side.",
 			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("NOMAD_TLS_SERVER_NAME", ""),
+				Description: "The server name used to verify the TLS certificate of the Nomad agent, instead of the host of the address.",
+			},
 		},
 
 		ConfigureFunc: providerConfigure,
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"nomad_acl_policies":     dataSourceAclPolicies(),
-			"nomad_acl_policy":       dataSourceAclPolicy(),
-			"nomad_acl_role":         dataSourceACLRole(),
-			"nomad_acl_roles":        dataSourceACLRoles(),
-			"nomad_acl_token":        dataSourceACLToken(),
-			"nomad_acl_tokens":       dataSourceACLTokens(),
-			"nomad_allocations":      dataSourceAllocations(),
-			"nomad_datacenters":      dataSourceDatacenters(),
-			"nomad_deployments":      dataSourceDeployments(),
-			"nomad_job":              dataSourceJob(),
-			"nomad_job_parser":       dataSourceJobParser(),
-			"nomad_jwks":             dataSourceJWKS(),
-			"nomad_namespace":        dataSourceNamespace(),
-			"nomad_namespaces":       dataSourceNamespaces(),
-			"nomad_node_pool":        dataSourceNodePool(),
-			"nomad_node_pools":       dataSourceNodePools(),
-			"nomad_plugin":           dataSourcePlugin(),
-			"nomad_plugins":          dataSourcePlugins(),
-			"nomad_scaling_policies": dataSourceScalingPolicies(),
-			"nomad_scaling_policy":   dataSourceScalingPolicy(),
-			"nomad_scheduler_config": dataSourceSchedulerConfig(),
-			"nomad_regions":          dataSourceRegions(),
-			"nomad_volumes":          dataSourceVolumes(),
-			"nomad_variable":         dataSourceVariable(),
+			"nomad_acl_policies":      dataSourceAclPolicies(),
+			"nomad_acl_policy":        dataSourceAclPolicy(),
+			"nomad_acl_role":          dataSourceACLRole(),
+			"nomad_acl_roles":         dataSourceACLRoles(),
+			"nomad_acl_token":         dataSourceACLToken(),
+			"nomad_acl_token_self":    dataSourceACLTokenSelf(),
+			"nomad_acl_tokens":        dataSourceACLTokens(),
+			"nomad_agent_health":      dataSourceAgentHealth(),
+			"nomad_allocations":       dataSourceAllocations(),
+			"nomad_datacenters":       dataSourceDatacenters(),
+			"nomad_deployments":       dataSourceDeployments(),
+			"nomad_job":               dataSourceJob(),
+			"nomad_job_parser":        dataSourceJobParser(),
+			"nomad_jwks":              dataSourceJWKS(),
+			"nomad_namespace":         dataSourceNamespace(),
+			"nomad_namespaces":        dataSourceNamespaces(),
+			"nomad_node":              dataSourceNode(),
+			"nomad_node_pool":         dataSourceNodePool(),
+			"nomad_node_pools":        dataSourceNodePools(),
+			"nomad_plugin":            dataSourcePlugin(),
+			"nomad_plugins":           dataSourcePlugins(),
+			"nomad_scaling_policies":  dataSourceScalingPolicies(),
+			"nomad_scaling_policy":    dataSourceScalingPolicy(),
+			"nomad_scheduler_config":  dataSourceSchedulerConfig(),
+			"nomad_service":           dataSourceService(),
+			"nomad_service_instances": dataSourceServiceInstances(),
+			"nomad_services":          dataSourceServices(),
+			"nomad_regions":           dataSourceRegions(),
+			"nomad_volumes":           dataSourceVolumes(),
+			"nomad_variable":          dataSourceVariable(),
+			"nomad_variables":         dataSourceVariables(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"nomad_acl_auth_method":         resourceACLAuthMethod(),
-			"nomad_acl_binding_rule":        resourceACLBindingRule(),
-			"nomad_acl_policy":              resourceACLPolicy(),
-			"nomad_acl_role":                resourceACLRole(),
-			"nomad_acl_token":               resourceACLToken(),
-			"nomad_csi_volume":              resourceCSIVolume(),
-			"nomad_csi_volume_registration": resourceCSIVolumeRegistration(),
-			"nomad_external_volume":         resourceExternalVolume(),
-			"nomad_job":                     resourceJob(),
-			"nomad_namespace":               resourceNamespace(),
-			"nomad_node_pool":               resourceNodePool(),
-			"nomad_quota_specification":     resourceQuotaSpecification(),
-			"nomad_sentinel_policy":         resourceSentinelPolicy(),
-			"nomad_volume":                  resourceVolume(),
-			"nomad_scheduler_config":        resourceSchedulerConfig(),
-			"nomad_variable":                resourceVariable(),
+			"nomad_acl_auth_method":           resourceACLAuthMethod(),
+			"nomad_acl_binding_rule":          resourceACLBindingRule(),
+			"nomad_acl_policy":                resourceACLPolicy(),
+			"nomad_acl_role":                  resourceACLRole(),
+			"nomad_acl_token":                 resourceACLToken(),
+			"nomad_csi_volume":                resourceCSIVolume(),
+			"nomad_csi_volume_registration":   resourceCSIVolumeRegistration(),
+			"nomad_external_volume":           resourceExternalVolume(),
+			"nomad_job":                       resourceJob(),
+			"nomad_namespace":                 resourceNamespace(),
+			"nomad_node_pool":                 resourceNodePool(),
+			"nomad_operator_autopilot_config": resourceOperatorAutopilotConfig(),
+			"nomad_operator_keyring":          resourceOperatorKeyring(),
+			"nomad_quota_specification":       resourceQuotaSpecification(),
+			"nomad_sentinel_policy":           resourceSentinelPolicy(),
+			"nomad_volume":                    resourceVolume(),
+			"nomad_scheduler_config":          resourceSchedulerConfig(),
+			"nomad_variable":                  resourceVariable(),
 		},
 	}
 }
@@ -217,6 +286,9 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	conf := api.DefaultConfig()
 	conf.Address = d.Get("address").(string)
 	conf.SecretID = d.Get("secret_id").(string)
+	if err := validateAddress(conf.Address); err != nil {
+		return nil, err
+	}
 
 	if region, ok := d.GetOk("region"); ok {
 		conf.Region = region.(string)
@@ -254,13 +326,18 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	conf.TLSConfig.ClientCertPEM = []byte(d.Get("cert_pem").(string))
 	conf.TLSConfig.ClientKeyPEM = []byte(d.Get("key_pem").(string))
 	conf.TLSConfig.Insecure = d.Get("skip_verify").(bool)
+	conf.TLSConfig.TLSServerName = d.Get("tls_server_name").(string)
 
-	if _, ok := os.LookupEnv("TF_ACC"); ok {
+	// The API client dials unix socket addresses with a client of its own.
+	if _, ok := os.LookupEnv("TF_ACC"); ok && !strings.HasPrefix(conf.Address, "unix://") {
 		// Revert the Nomad API client to non-pooled to avoid EOF errors when
 		// running the test suite since it instantiates the provider multiple
 		// times, creating several clients in parallel.
 		// https://github.com/hashicorp/nomad/pull/12492
 		conf.HttpClient = nonPooledHttpClient()
+		if err := api.ConfigureTLS(conf.HttpClient, conf.TLSConfig); err != nil {
+			return nil, fmt.Errorf("failed to configure TLS for the Nomad API: %s", err)
+		}
 	}
 
 	// Set headers if provided
@@ -288,6 +365,22 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
 	consulToken := d.Get("consul_token").(string)
 
+	defaultConsulPartition := d.Get("default_consul_partition").(string)
+	allowedConsulPartitions := make([]string, 0, d.Get("allowed_consul_partitions").(*schema.Set).Len())
+	for _, partition := range d.Get("allowed_consul_partitions").(*schema.Set).List() {
+		allowedConsulPartitions = append(allowedConsulPartitions, partition.(string))
+	}
+	if defaultConsulPartition != "" && len(allowedConsulPartitions) > 0 &&
+		!slices.Contains(allowedConsulPartitions, defaultConsulPartition) {
+		return nil, fmt.Errorf("default_consul_partition %q is not in allowed_consul_partitions", defaultConsulPartition)
+	}
+
+	if maxRequests := d.Get("max_concurrent_requests").(int); maxRequests > 0 {
+		if err := limitConcurrentRequests(conf, maxRequests); err != nil {
+			return nil, err
+		}
+	}
+
 	client, err := api.NewClient(conf)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure Nomad API: %s", err)
@@ -298,13 +391,141 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		client:      client,
 		vaultToken:  &vaultToken,
 		consulToken: &consulToken,
+
+		defaultConsulNamespace:  d.Get("default_consul_namespace").(string),
+		defaultConsulPartition:  defaultConsulPartition,
+		allowedConsulPartitions: allowedConsulPartitions,
+		defaultVaultNamespace:   d.Get("default_vault_namespace").(string),
+		defaultJobPriority:      d.Get("default_job_priority").(int),
+		trackJobSubmissions:     d.Get("track_job_submissions").(bool),
 	}
 
 	return res, nil
 }
 
+// validateAddress checks that the address of the Nomad agent is an HTTP(S)
+// URL, or a unix:// URL to a socket that exists.
+func validateAddress(address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %s", address, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return nil
+	case "unix":
+		info, err := os.Stat(u.Path)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("invalid address %q: unix socket %q doesn't exist", address, u.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %s", address, err)
+		}
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("invalid address %q: %q is not a unix socket", address, u.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid address %q: the scheme must be http, https or unix", address)
+	}
+}
+
+// limitConcurrentRequests caps the number of requests the Nomad API client
+// sends at the same time by wrapping the transport of its HTTP client with a
+// semaphore.
+func limitConcurrentRequests(conf *api.Config, maxRequests int) error {
+	if conf.HttpClient == nil {
+		// The API client only creates its default HTTP client, and configures
+		// its TLS, when none is set, so it must be done here.
+		httpClient, err := defaultAPIHttpClient(conf)
+		if err != nil {
+			return err
+		}
+		conf.HttpClient = httpClient
+	}
+
+	conf.HttpClient.Transport = &limitedTransport{
+		base: conf.HttpClient.Transport,
+		sem:  make(chan struct{}, maxRequests),
+	}
+	return nil
+}
+
+// defaultAPIHttpClient returns the HTTP client the Nomad API client uses for
+// the address of conf when none is set. As done by the API client, unix
+// socket addresses are dialed by the HTTP client and replaced by a dummy
+// HTTP address.
+func defaultAPIHttpClient(conf *api.Config) (*http.Client, error) {
+	httpClient := pooledHttpClient()
+	if socket, ok := strings.CutPrefix(conf.Address, "unix://"); ok {
+		httpClient.Transport.(*http.Transport).DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+		conf.Address = "http://127.0.0.1"
+	}
+
+	if err := api.ConfigureTLS(httpClient, conf.TLSConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure TLS for the Nomad API: %s", err)
+	}
+	return httpClient, nil
+}
+
+// limitedTransport is an http.RoundTripper that limits the number of requests
+// in flight to the capacity of sem. A request holds its slot until its
+// response body is closed.
+type limitedTransport struct {
+	base http.RoundTripper
+	sem  chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		<-t.sem
+		return nil, err
+	}
+	resp.Body = &releaseOnCloseBody{ReadCloser: resp.Body, release: func() { <-t.sem }}
+	return resp, nil
+}
+
+// CloseIdleConnections closes the idle connections of the wrapped transport,
+// so http.Client.CloseIdleConnections keeps working.
+func (t *limitedTransport) CloseIdleConnections() {
+	if tr, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		tr.CloseIdleConnections()
+	}
+}
+
+// releaseOnCloseBody calls release the first time the body is closed.
+type releaseOnCloseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+func pooledHttpClient() *http.Client {
+	return configureHttpClient(cleanhttp.DefaultPooledClient())
+}
+
 func nonPooledHttpClient() *http.Client {
-	httpClient := cleanhttp.DefaultClient()
+	return configureHttpClient(cleanhttp.DefaultClient())
+}
+
+func configureHttpClient(httpClient *http.Client) *http.Client {
 	transport := httpClient.Transport.(*http.Transport)
 	transport.TLSHandshakeTimeout = 10 * time.Second
 	transport.TLSClientConfig = &tls.Config{
@@ -5,14 +5,22 @@ package nomad
 
 import (
 	"context"
+	"encoding/pem"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -46,6 +54,140 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestLimitConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`"127.0.0.1:4647"`))
+	})
+
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	socket := filepath.Join(t.TempDir(), "nomad.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	unixSrv := &http.Server{Handler: handler}
+	go unixSrv.Serve(listener)
+	defer unixSrv.Close()
+
+	for _, address := range []string{srv.URL, "unix://" + socket} {
+		atomic.StoreInt32(&maxInFlight, 0)
+
+		conf := api.DefaultConfig()
+		conf.Address = address
+		if err := limitConcurrentRequests(conf, 2); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		client, err := api.NewClient(conf)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Status().Leader(); err != nil {
+					t.Errorf("%s: err: %s", address, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got := atomic.LoadInt32(&maxInFlight); got != 2 {
+			t.Fatalf("%s: got %d concurrent requests, want 2", address, got)
+		}
+	}
+}
+
+func TestProviderConfigure_tlsServerName(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"127.0.0.1:4647"`))
+	}))
+	defer srv.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+
+	// The test server certificate is valid for example.com, but not for
+	// nomad.example.org.
+	for serverName, valid := range map[string]bool{
+		"example.com":       true,
+		"nomad.example.org": false,
+	} {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+			"address":         srv.URL,
+			"ca_pem":          string(caPEM),
+			"tls_server_name": serverName,
+		})
+		meta, err := providerConfigure(d)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		_, err = meta.(ProviderConfig).client.Status().Leader()
+		if valid && err != nil {
+			t.Fatalf("expected certificate to be valid for %q: %s", serverName, err)
+		}
+		if !valid && err == nil {
+			t.Fatalf("expected certificate to be invalid for %q", serverName)
+		}
+	}
+}
+
+func TestProviderConfigure_unixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "nomad.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`"127.0.0.1:4647"`))
+	})}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"address": "unix://" + socket,
+	})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	leader, err := meta.(ProviderConfig).client.Status().Leader()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if leader != "127.0.0.1:4647" {
+		t.Fatalf("unexpected leader %q", leader)
+	}
+
+	for address, expected := range map[string]string{
+		"unix://" + socket + ".missing": "doesn't exist",
+		"unix://" + t.TempDir():         "is not a unix socket",
+		"tcp://127.0.0.1:4646":          "the scheme must be http, https or unix",
+	} {
+		d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+			"address": address,
+		})
+		_, err := providerConfigure(d)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error containing %q for %q, got: %v", expected, address, err)
+		}
+	}
+}
+
 var testProvider *schema.Provider
 var testProviders map[string]*schema.Provider
 
@@ -131,6 +273,13 @@ func testCheckVaultEnabled(t *testing.T) {
 	}
 }
 
+func testCheckGossipEncryptionEnabled(t *testing.T) {
+	client := testProvider.Meta().(ProviderConfig).client
+	if _, err := client.Agent().ListKeys(); err != nil {
+		t.Skip("gossip encryption not enabled: ", err)
+	}
+}
+
 func testCheckConsulEnabled(t *testing.T) {
 	client := testProvider.Meta().(ProviderConfig).client
 	consulEnabled := false
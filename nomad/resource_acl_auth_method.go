@@ -498,6 +498,9 @@ func packStringArray(array []string) []interface{} {
 	return packed
 }
 
+// packStringMap converts stringMap for a TypeMap attribute. Missing maps are
+// packed as empty maps, and TypeMap attributes are compared by key, so claim
+// mappings returned by Nomad in a different order don't cause a diff.
 func packStringMap(stringMap map[string]string) map[string]interface{} {
 	packed := make(map[string]interface{})
 	for k, v := range stringMap {
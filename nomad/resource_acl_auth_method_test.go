@@ -40,6 +40,72 @@ func TestResourceACLAuthMethod(t *testing.T) {
 	})
 }
 
+func TestResourceACLAuthMethod_claimMappingsOrder(t *testing.T) {
+	testResourceName := acctest.RandomWithPrefix("tf-nomad-test")
+	claimMappings := []string{
+		`"http://nomad.internal/name": "name"`,
+		`"http://nomad.internal/email": "email"`,
+		`"http://nomad.internal/team": "team"`,
+	}
+	listClaimMappings := []string{
+		`"http://nomad.internal/roles": "roles"`,
+		`"http://nomad.internal/groups": "groups"`,
+	}
+	reversed := func(in []string) []string {
+		out := slices.Clone(in)
+		slices.Reverse(out)
+		return out
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.4.4-dev") },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceACLAuthMethodConfig_claimMappings(testResourceName, claimMappings, listClaimMappings),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_acl_auth_method.test", "config.0.claim_mappings.%", "3"),
+					resource.TestCheckResourceAttr("nomad_acl_auth_method.test", "config.0.claim_mappings.http://nomad.internal/team", "team"),
+					resource.TestCheckResourceAttr("nomad_acl_auth_method.test", "config.0.list_claim_mappings.%", "2"),
+					resource.TestCheckResourceAttr("nomad_acl_auth_method.test", "config.0.list_claim_mappings.http://nomad.internal/groups", "groups"),
+				),
+			},
+			// The mappings read back from Nomad and declared in a different
+			// order must not cause a diff.
+			{
+				Config:   testResourceACLAuthMethodConfig_claimMappings(testResourceName, reversed(claimMappings), reversed(listClaimMappings)),
+				PlanOnly: true,
+			},
+		},
+		CheckDestroy: testResourceACLAuthMethodCheckDestroy(testResourceName),
+	})
+}
+
+func testResourceACLAuthMethodConfig_claimMappings(name string, claimMappings, listClaimMappings []string) string {
+	return fmt.Sprintf(`
+resource "nomad_acl_auth_method" "test" {
+  name           = "%s"
+  type           = "OIDC"
+  token_locality = "global"
+  max_token_ttl  = "10m0s"
+
+  config {
+    oidc_discovery_url    = "https://uk.auth0.com/"
+    oidc_client_id        = "someclientid"
+    oidc_client_secret    = "someclientsecret-t"
+    bound_audiences       = ["someclientid"]
+    allowed_redirect_uris = ["http://localhost:4649/oidc/callback"]
+    claim_mappings = {
+      %s
+    }
+    list_claim_mappings = {
+      %s
+    }
+  }
+}
+`, name, strings.Join(claimMappings, "\n      "), strings.Join(listClaimMappings, "\n      "))
+}
+
 func testResourceACLAuthMethodConfig(name, uiCallback string, defaultVal bool) string {
 	return fmt.Sprintf(`
 resource "nomad_acl_auth_method" "test" {
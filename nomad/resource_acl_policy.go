@@ -9,6 +9,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -40,9 +41,10 @@ func resourceACLPolicy() *schema.Resource {
 			},
 
 			"rules_hcl": {
-				Description: "HCL or JSON representation of the rules to enforce on this policy. Use file() to specify a file as input.",
-				Required:    true,
-				Type:        schema.TypeString,
+				Description:  "HCL or JSON representation of the rules to enforce on this policy. Use file() to specify a file as input.",
+				Required:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateACLPolicyRules,
 			},
 
 			"job_acl": {
@@ -80,6 +82,22 @@ func resourceACLPolicy() *schema.Resource {
 	}
 }
 
+// validateACLPolicyRules is a schema.SchemaValidateFunc that checks that the
+// rules are well-formed HCL or JSON, using the same HCL parser as Nomad, so
+// syntax errors are reported during plan. The rules are not otherwise
+// validated since the set of valid policy rules depends on the Nomad version.
+func validateACLPolicyRules(i interface{}, k string) ([]string, []error) {
+	rules, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+
+	if _, err := hcl.Parse(rules); err != nil {
+		return nil, []error{fmt.Errorf("failed to parse %q: %v", k, err)}
+	}
+	return nil, nil
+}
+
 func parseWorkloadIdentity(workloadIdentity interface{}) (*api.JobACL, error) {
 	jobACLs, ok := workloadIdentity.([]interface{})
 	if !ok || len(jobACLs) > 1 {
@@ -6,6 +6,7 @@ package nomad
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -111,6 +112,34 @@ func TestResourceACLPolicy_update(t *testing.T) {
 	})
 }
 
+func TestResourceACLPolicy_invalidRules(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-nomad-test")
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceACLPolicy_invalidRulesConfig(name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`failed to parse "rules_hcl": At \d+:\d+`),
+			},
+		},
+	})
+}
+
+func testResourceACLPolicy_invalidRulesConfig(name string) string {
+	return fmt.Sprintf(`
+resource "nomad_acl_policy" "test" {
+  name = "%s"
+  rules_hcl = <<EOT
+namespace "default" {
+  policy = "read"
+  capabilities = ["submit-job"]
+EOT
+}
+`, name)
+}
+
 func testResourceACLPolicy_initialConfig(name string) string {
 	return fmt.Sprintf(`
 resource "nomad_acl_policy" "test" {
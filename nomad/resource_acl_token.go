@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
@@ -21,6 +22,8 @@ func resourceACLToken() *schema.Resource {
 		Read:   resourceACLTokenRead,
 		Exists: resourceACLTokenExists,
 
+		CustomizeDiff: resourceACLTokenCustomizeDiff,
+
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
@@ -45,6 +48,7 @@ func resourceACLToken() *schema.Resource {
 			"type": {
 				Description: "The type of token to create, 'client' or 'management'.",
 				Required:    true,
+				ForceNew:    true,
 				Type:        schema.TypeString,
 			},
 			"policies": {
@@ -61,16 +65,19 @@ func resourceACLToken() *schema.Resource {
 					Schema: map[string]*schema.Schema{
 						"id": {
 							Type:        schema.TypeString,
-							Required:    true,
+							Optional:    true,
+							Computed:    true,
 							Description: "The ID of the ACL role to link.",
 						},
 						"name": {
 							Type:        schema.TypeString,
+							Optional:    true,
 							Computed:    true,
-							Description: "The name of the ACL role linked.",
+							Description: "The name of the ACL role to link. Used to look up the role ID if `id` is not set.",
 						},
 					},
 				},
+				Set: resourceACLTokenRoleHash,
 			},
 			"global": {
 				Description: "Whether the token should be replicated to all regions or not.",
@@ -96,6 +103,34 @@ func resourceACLToken() *schema.Resource {
 				Computed:    true,
 				Type:        schema.TypeString,
 			},
+			"store_secret_in_variable": {
+				Description: "Store the secret ID of the token in an item of a Nomad variable. The item is removed when the token is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Description:      "The path of the variable.",
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: pathValidation(),
+						},
+						"namespace": {
+							Description: "The namespace of the variable.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     api.DefaultNamespace,
+						},
+						"key": {
+							Description: "The variable item in which the secret ID is stored.",
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "secret_id",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -108,6 +143,9 @@ func resourceACLTokenCreate(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return err
 	}
+	if err := resolveACLTokenRoles(client, token.Roles); err != nil {
+		return err
+	}
 
 	// create our token
 	log.Println("[DEBUG] Creating ACL token")
@@ -118,6 +156,10 @@ func resourceACLTokenCreate(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Created ACL token %q", resp.AccessorID)
 	d.SetId(resp.AccessorID)
 
+	if err := resourceACLTokenStoreSecret(client, d.Get("store_secret_in_variable"), resp.SecretID); err != nil {
+		return err
+	}
+
 	return resourceACLTokenRead(d, meta)
 }
 
@@ -129,6 +171,9 @@ func resourceACLTokenUpdate(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return err
 	}
+	if err := resolveACLTokenRoles(client, token.Roles); err != nil {
+		return err
+	}
 
 	// update the token
 	log.Printf("[DEBUG] Updating ACL token %q", d.Id())
@@ -138,6 +183,16 @@ func resourceACLTokenUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 	log.Printf("[DEBUG] Updated ACL token %q", d.Id())
 
+	if d.HasChange("store_secret_in_variable") {
+		oldStore, newStore := d.GetChange("store_secret_in_variable")
+		if err := resourceACLTokenRemoveSecret(client, oldStore); err != nil {
+			return err
+		}
+		if err := resourceACLTokenStoreSecret(client, newStore, d.Get("secret_id").(string)); err != nil {
+			return err
+		}
+	}
+
 	return resourceACLTokenRead(d, meta)
 }
 
@@ -154,7 +209,46 @@ func resourceACLTokenDelete(d *schema.ResourceData, meta interface{}) error {
 	}
 	log.Printf("[DEBUG] Deleted ACL token %q", accessor)
 
-	return nil
+	return resourceACLTokenRemoveSecret(client, d.Get("store_secret_in_variable"))
+}
+
+// resourceACLTokenStoreSecret writes the secret ID of the token into the
+// variable item configured in store_secret_in_variable, if any, leaving the
+// other items of the variable untouched.
+func resourceACLTokenStoreSecret(client *api.Client, raw interface{}, secretID string) error {
+	store := raw.([]interface{})
+	if len(store) == 0 || store[0] == nil {
+		return nil
+	}
+	config := store[0].(map[string]interface{})
+	key := config["key"].(string)
+
+	variable := &api.Variable{
+		Path:      config["path"].(string),
+		Namespace: config["namespace"].(string),
+		Items:     api.VariableItems{key: secretID},
+	}
+	log.Printf("[DEBUG] Storing ACL token secret in variable %s@%s", variable.Path, variable.Namespace)
+	return resourceVariableMergeWrite(client, variable, map[string]any{key: nil})
+}
+
+// resourceACLTokenRemoveSecret removes the variable item configured in
+// store_secret_in_variable, if any. The variable is deleted if it has no other
+// items.
+func resourceACLTokenRemoveSecret(client *api.Client, raw interface{}) error {
+	store := raw.([]interface{})
+	if len(store) == 0 || store[0] == nil {
+		return nil
+	}
+	config := store[0].(map[string]interface{})
+
+	variable := &api.Variable{
+		Path:      config["path"].(string),
+		Namespace: config["namespace"].(string),
+		Items:     api.VariableItems{},
+	}
+	log.Printf("[DEBUG] Removing ACL token secret from variable %s@%s", variable.Path, variable.Namespace)
+	return resourceVariableMergeWrite(client, variable, map[string]any{config["key"].(string): nil})
 }
 
 func resourceACLTokenRead(d *schema.ResourceData, meta interface{}) error {
@@ -215,6 +309,74 @@ func resourceACLTokenExists(d *schema.ResourceData, meta interface{}) (bool, err
 	return true, nil
 }
 
+// resourceACLTokenCustomizeDiff looks up the IDs of the roles referenced only
+// by name, so they match the roles read back into state. Roles that don't
+// exist yet, such as roles created in the same apply, are resolved when the
+// token is created or updated.
+func resourceACLTokenCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("role") {
+		return nil
+	}
+	client := meta.(ProviderConfig).client
+
+	roles := d.Get("role").(*schema.Set).List()
+	resolved := false
+	for _, raw := range roles {
+		role := raw.(map[string]interface{})
+		name := role["name"].(string)
+		if role["id"].(string) != "" || name == "" {
+			continue
+		}
+
+		aclRole, _, err := client.ACLRoles().GetByName(name, nil)
+		if err != nil {
+			if strings.Contains(err.Error(), "404") {
+				continue
+			}
+			return fmt.Errorf("error reading ACL role %q: %s", name, err.Error())
+		}
+		role["id"] = aclRole.ID
+		resolved = true
+	}
+
+	if resolved {
+		return d.SetNew("role", roles)
+	}
+	return nil
+}
+
+// resolveACLTokenRoles sets the ID of the role links that only reference the
+// role by name, using the roles API. Links with an ID are sent without their
+// name, which Nomad sets from the role.
+func resolveACLTokenRoles(client *api.Client, roles []*api.ACLTokenRoleLink) error {
+	for _, link := range roles {
+		if link.ID != "" {
+			link.Name = ""
+			continue
+		}
+
+		log.Printf("[DEBUG] Looking up ACL role %q", link.Name)
+		role, _, err := client.ACLRoles().GetByName(link.Name, nil)
+		if err != nil {
+			return fmt.Errorf("error reading ACL role %q: %s", link.Name, err.Error())
+		}
+		link.ID, link.Name = role.ID, ""
+	}
+	return nil
+}
+
+// resourceACLTokenRoleHash hashes the roles of a token by ID, or by name for
+// roles whose ID isn't known yet, so a role referenced by either matches the
+// role read back into state.
+func resourceACLTokenRoleHash(v interface{}) int {
+	role := v.(map[string]interface{})
+	if id, _ := role["id"].(string); id != "" {
+		return schema.HashString(id)
+	}
+	name, _ := role["name"].(string)
+	return schema.HashString("name:" + name)
+}
+
 // resourceACLTokenGenerate takes the resource data and converts this into a
 // valid ACL Token object. Any error returned is fatal to this run of Terraform
 // and indicates a user error when configuring certain schema values.
@@ -228,7 +390,11 @@ func resourceACLTokenGenerate(d *schema.ResourceData) (*api.ACLToken, error) {
 	roles := make([]*api.ACLTokenRoleLink, 0, len(d.Get("role").(*schema.Set).List()))
 	for _, raw := range d.Get("role").(*schema.Set).List() {
 		role := raw.(map[string]interface{})
-		roles = append(roles, &api.ACLTokenRoleLink{ID: role["id"].(string)})
+		id, name := role["id"].(string), role["name"].(string)
+		if id == "" && name == "" {
+			return nil, fmt.Errorf("role requires either id or name to be set")
+		}
+		roles = append(roles, &api.ACLTokenRoleLink{ID: id, Name: name})
 	}
 
 	token := api.ACLToken{
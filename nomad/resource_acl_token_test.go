@@ -49,17 +49,25 @@ func TestResourceACLToken_basic(t *testing.T) {
 }
 
 func TestResourceACLToken_update(t *testing.T) {
+	var secretID string
+
 	resource.Test(t, resource.TestCase{
 		Providers: testProviders,
 		PreCheck:  func() { testAccPreCheck(t) },
 		Steps: []resource.TestStep{
 			{
 				Config: testResourceACLToken_initialConfig(),
-				Check:  testResourceACLToken_initialCheck(),
+				Check: resource.ComposeTestCheckFunc(
+					testResourceACLToken_initialCheck(),
+					testResourceACLToken_secretIDCheck(&secretID),
+				),
 			},
 			{
 				Config: testResourceACLToken_updateConfig(),
-				Check:  testResourceACLToken_updateCheck(),
+				Check: resource.ComposeTestCheckFunc(
+					testResourceACLToken_updateCheck(),
+					testResourceACLToken_secretIDCheck(&secretID),
+				),
 			},
 		},
 
@@ -86,7 +94,8 @@ func TestResourceACLToken_Expiration(t *testing.T) {
 
 func TestResourceACLToken_RoleLink(t *testing.T) {
 
-	config, testFn := testResourceACLTokenRoleLink()
+	config, testFn := testResourceACLTokenRoleLink("id = nomad_acl_role.test.id")
+	configByName, _ := testResourceACLTokenRoleLink("name = nomad_acl_role.test.name")
 
 	resource.Test(t, resource.TestCase{
 		Providers: testProviders,
@@ -96,11 +105,86 @@ func TestResourceACLToken_RoleLink(t *testing.T) {
 				Config: config,
 				Check:  testFn,
 			},
+			{
+				// Referencing the same role by name doesn't change the token.
+				Config:   configByName,
+				PlanOnly: true,
+			},
 		},
 		CheckDestroy: testResourceACLTokenCheckDestroy,
 	})
 }
 
+func TestResourceACLToken_RoleLinkByName(t *testing.T) {
+
+	config, testFn := testResourceACLTokenRoleLink("name = nomad_acl_role.test.name")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.4.0-beta.1") },
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  testFn,
+			},
+		},
+		CheckDestroy: testResourceACLTokenCheckDestroy,
+	})
+}
+
+func TestResourceACLToken_StoreSecretInVariable(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.4.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceACLTokenStoreSecretConfig,
+				Check: func(s *terraform.State) error {
+					secretID := s.RootModule().Resources["nomad_acl_token.test"].Primary.Attributes["secret_id"]
+
+					client := testProvider.Meta().(ProviderConfig).client
+					variable, _, err := client.Variables().Read("tf-acc-test/token", nil)
+					if err != nil {
+						return fmt.Errorf("error reading variable: %s", err)
+					}
+					if variable.Items["token"] != secretID {
+						return errors.New("variable item doesn't match the token secret ID")
+					}
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(s *terraform.State) error {
+			if err := testResourceACLTokenCheckDestroy(s); err != nil {
+				return err
+			}
+
+			client := testProvider.Meta().(ProviderConfig).client
+			variable, _, err := client.Variables().Peek("tf-acc-test/token", nil)
+			if err != nil {
+				return fmt.Errorf("error reading variable: %s", err)
+			}
+			if variable != nil {
+				return errors.New("variable has not been deleted")
+			}
+			return nil
+		},
+	})
+}
+
+const testResourceACLTokenStoreSecretConfig = `
+resource "nomad_acl_token" "test" {
+  name     = "Terraform Test Token"
+  type     = "client"
+  policies = ["dev"]
+
+  store_secret_in_variable {
+    path = "tf-acc-test/token"
+    key  = "token"
+  }
+}
+`
+
 func testResourceACLToken_initialConfig() string {
 	return `
 resource "nomad_acl_token" "test" {
@@ -276,6 +360,32 @@ func testResourceACLToken_updateCheck() resource.TestCheckFunc {
 	}
 }
 
+// testResourceACLToken_secretIDCheck stores the token secret ID in secretID
+// the first time it's called and verifies that it hasn't changed afterwards.
+func testResourceACLToken_secretIDCheck(secretID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		resourceState := s.Modules[0].Resources["nomad_acl_token.test"]
+		if resourceState == nil {
+			return errors.New("resource not found in state")
+		}
+
+		instanceState := resourceState.Primary
+		if instanceState == nil {
+			return errors.New("resource has no primary instance")
+		}
+
+		got := instanceState.Attributes["secret_id"]
+		if *secretID == "" {
+			*secretID = got
+			return nil
+		}
+		if got != *secretID {
+			return fmt.Errorf("expected secret_id to remain %q, got %q", *secretID, got)
+		}
+		return nil
+	}
+}
+
 func testResourceACLTokenExpiration() (string, resource.TestCheckFunc) {
 
 	const (
@@ -368,7 +478,7 @@ resource "nomad_acl_token" "test" {
 	return config, checkFn
 }
 
-func testResourceACLTokenRoleLink() (string, resource.TestCheckFunc) {
+func testResourceACLTokenRoleLink(roleRef string) (string, resource.TestCheckFunc) {
 
 	const (
 		name   = "terraform-token-test"
@@ -403,10 +513,10 @@ resource "nomad_acl_token" "test" {
   global = %s
 
   role {
-    id = nomad_acl_role.test.id
+    %s
   }
 }
-`, name, typ, global)
+`, name, typ, global, roleRef)
 
 	checkFn := func(s *terraform.State) error {
 		resourceState := s.Modules[0].Resources["nomad_acl_token.test"]
@@ -465,6 +575,9 @@ resource "nomad_acl_token" "test" {
 		if len(token.Roles) != 1 {
 			return fmt.Errorf("expected %d roles, got %v from the API", 1, token.Roles)
 		}
+		if token.Roles[0].Name != "terraform-token-test" {
+			return fmt.Errorf("expected role to be %q, is %q in API", "terraform-token-test", token.Roles[0].Name)
+		}
 		return nil
 	}
 
@@ -26,7 +26,8 @@ func resourceCSIVolume() *schema.Resource {
 		CreateContext: resourceCSIVolumeCreate,
 		UpdateContext: resourceCSIVolumeCreate,
 		DeleteContext: resourceCSIVolumeDelete,
-		Read:          resourceCSIVolumeRead,
+		Read:          resourceCSIVolumeReadWithPlugin,
+		CustomizeDiff: resourceCSIVolumeCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(10 * time.Minute),
@@ -83,7 +84,7 @@ func resourceCSIVolume() *schema.Resource {
 			},
 
 			"capacity_min": {
-				Description:      "Defines how small the volume can be. The storage provider may return a volume that is larger than this value.",
+				Description:      "Defines how small the volume can be. The storage provider may return a volume that is larger than this value. Increasing it expands the volume in place if the plugin supports it.",
 				Optional:         true,
 				Type:             schema.TypeString,
 				StateFunc:        capacityStateFunc,
@@ -91,7 +92,7 @@ func resourceCSIVolume() *schema.Resource {
 			},
 
 			"capacity_max": {
-				Description:      "Defines how large the volume can be. The storage provider may return a volume that is smaller than this value.",
+				Description:      "Defines how large the volume can be. The storage provider may return a volume that is smaller than this value. Lowering it below the current capacity replaces the volume.",
 				Optional:         true,
 				Type:             schema.TypeString,
 				StateFunc:        capacityStateFunc,
@@ -281,6 +282,12 @@ func resourceCSIVolume() *schema.Resource {
 				Type:     schema.TypeInt,
 			},
 
+			"plugin_supports_expand": {
+				Description: "Whether the CSI plugin supports expanding volumes in place.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+
 			"controller_required": {
 				Computed: true,
 				Type:     schema.TypeBool,
@@ -297,8 +304,9 @@ func resourceCSIVolume() *schema.Resource {
 			},
 
 			"plugin_provider": {
-				Computed: true,
-				Type:     schema.TypeString,
+				Description: "The name of the storage provider of the CSI plugin.",
+				Computed:    true,
+				Type:        schema.TypeString,
 			},
 
 			"plugin_provider_version": {
@@ -408,6 +416,76 @@ func resourceCSIVolumeRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// resourceCSIVolumeReadWithPlugin reads the volume and whether its plugin
+// supports expanding it.
+func resourceCSIVolumeReadWithPlugin(d *schema.ResourceData, meta interface{}) error {
+	if err := resourceCSIVolumeRead(d, meta); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+
+	client := meta.(ProviderConfig).client
+	pluginID := d.Get("plugin_id").(string)
+	plugin, _, err := client.CSIPlugins().Info(pluginID, nil)
+	if err != nil {
+		log.Printf("[WARN] failed to read CSI plugin %q: %s", pluginID, err)
+		return nil
+	}
+	d.Set("plugin_supports_expand", csiPluginSupportsExpand(plugin))
+
+	return nil
+}
+
+// csiPluginSupportsExpand returns true if the controllers of the plugin, or
+// its nodes if it doesn't require controllers, support expanding volumes.
+func csiPluginSupportsExpand(plugin *api.CSIPlugin) bool {
+	if plugin.ControllerRequired {
+		for _, info := range plugin.Controllers {
+			if info != nil && info.ControllerInfo != nil && info.ControllerInfo.SupportsExpand {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, info := range plugin.Nodes {
+		if info != nil && info.NodeInfo != nil && info.NodeInfo.SupportsExpand {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceCSIVolumeCustomizeDiff replaces the volume when capacity_max is
+// lowered below its current capacity, since volumes can't be shrunk. Other
+// capacity changes are applied in place, expanding the volume if needed.
+func resourceCSIVolumeCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !(d.HasChange("capacity_min") || d.HasChange("capacity_max")) {
+		return nil
+	}
+
+	if csiVolumeCapacityShrinks(d.Get("capacity_max").(string), d.Get("capacity").(int)) {
+		log.Printf("[DEBUG] capacity_max is lower than the current capacity, forcing replacement of CSI volume %q", d.Id())
+		return d.ForceNew("capacity_max")
+	}
+	return d.SetNewComputed("capacity")
+}
+
+// csiVolumeCapacityShrinks returns true if the requested maximum capacity is
+// lower than the current capacity of the volume.
+func csiVolumeCapacityShrinks(capacityMax string, current int) bool {
+	if capacityMax == "" || current == 0 {
+		return false
+	}
+	capMax, err := humanize.ParseBytes(capacityMax)
+	if err != nil {
+		return false
+	}
+	return capMax < uint64(current)
+}
+
 func resourceCSIVolumeCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	providerConfig := meta.(ProviderConfig)
 	client := providerConfig.client
@@ -500,7 +578,7 @@ func resourceCSIVolumeCreate(ctx context.Context, d *schema.ResourceData, meta i
 		log.Printf("[DEBUG] CSI volume %q created in namespace %q", volume.ID, volume.Namespace)
 		d.SetId(volume.ID)
 
-		err := resourceCSIVolumeRead(d, meta) // populate other computed attributes
+		err := resourceCSIVolumeReadWithPlugin(d, meta) // populate other computed attributes
 		if err != nil {
 			return retry.NonRetryableError(err)
 		}
@@ -136,6 +136,12 @@ resource "nomad_csi_volume" "test" {
 					if volume.PluginID != expected["plugin_id"] {
 						return fmt.Errorf("expected PluginID to be %s, got: %s", expected["plugin_id"], volume.PluginID)
 					}
+					if got := instanceState.Attributes["external_id"]; got == "" || got != volume.ExternalID {
+						return fmt.Errorf("expected external_id to be %s, got: %s", volume.ExternalID, got)
+					}
+					if got := instanceState.Attributes["plugin_provider"]; got != volume.Provider {
+						return fmt.Errorf("expected plugin_provider to be %s, got: %s", volume.Provider, got)
+					}
 
 					expectedCapacity := int64(10 * 1024 * 1024 * 1024)
 					if volume.Capacity != expectedCapacity {
@@ -428,3 +434,76 @@ func TestCheckCapacity(t *testing.T) {
 		})
 	}
 }
+
+func TestCSIVolumeCapacityShrinks(t *testing.T) {
+	cases := []struct {
+		capMax   string
+		current  int
+		expected bool
+	}{
+		// unset values never shrink the volume.
+		{"", 1024, false},
+		{"1KiB", 0, false},
+		// growing or keeping the capacity is done in place.
+		{"2KiB", 1024, false},
+		{"1KiB", 1024, false},
+		// a lower max capacity requires a new volume.
+		{"1KiB", 2048, true},
+	}
+	for _, tc := range cases {
+		name := fmt.Sprintf("%s-%d-%v", tc.capMax, tc.current, tc.expected)
+		t.Run(name, func(t *testing.T) {
+			test.Eq(t, tc.expected, csiVolumeCapacityShrinks(tc.capMax, tc.current))
+		})
+	}
+}
+
+func TestCSIPluginSupportsExpand(t *testing.T) {
+	expandController := &api.CSIInfo{ControllerInfo: &api.CSIControllerInfo{SupportsExpand: true}}
+	expandNode := &api.CSIInfo{NodeInfo: &api.CSINodeInfo{SupportsExpand: true}}
+	noExpand := &api.CSIInfo{
+		ControllerInfo: &api.CSIControllerInfo{},
+		NodeInfo:       &api.CSINodeInfo{},
+	}
+
+	cases := []struct {
+		name     string
+		plugin   *api.CSIPlugin
+		expected bool
+	}{
+		{
+			name: "controller expand",
+			plugin: &api.CSIPlugin{
+				ControllerRequired: true,
+				Controllers:        map[string]*api.CSIInfo{"a": noExpand, "b": expandController},
+			},
+			expected: true,
+		},
+		{
+			name: "controller required without expand",
+			plugin: &api.CSIPlugin{
+				ControllerRequired: true,
+				Controllers:        map[string]*api.CSIInfo{"a": noExpand},
+				Nodes:              map[string]*api.CSIInfo{"a": expandNode},
+			},
+			expected: false,
+		},
+		{
+			name: "node expand",
+			plugin: &api.CSIPlugin{
+				Nodes: map[string]*api.CSIInfo{"a": expandNode},
+			},
+			expected: true,
+		},
+		{
+			name:     "no plugins",
+			plugin:   &api.CSIPlugin{},
+			expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.Eq(t, tc.expected, csiPluginSupportsExpand(tc.plugin))
+		})
+	}
+}
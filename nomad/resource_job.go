@@ -5,37 +5,48 @@ package nomad
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"path"
 	"reflect"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec"
 	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"golang.org/x/exp/maps"
 
 	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper/pointer"
 )
 
 func resourceJob() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceJobRegister,
-		Update: resourceJobRegister,
-		Delete: resourceJobDeregister,
-		Read:   resourceJobRead,
+		CreateContext: resourceJobApply,
+		UpdateContext: resourceJobApply,
+		DeleteContext: resourceJobDestroy,
+		Read:          resourceJobRead,
 
 		CustomizeDiff: resourceJobCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Importer: &schema.ResourceImporter{
@@ -48,6 +59,7 @@ func resourceJob() *schema.Resource {
 				Required:         true,
 				Type:             schema.TypeString,
 				DiffSuppressFunc: jobspecDiffSuppress,
+				ValidateDiagFunc: jobspecWarnings,
 			},
 
 			"policy_override": {
@@ -56,6 +68,13 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"eval_priority": {
+				Description:  "The priority of the evaluations created when the job is registered or deregistered. Defaults to the priority of the job.",
+				Optional:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntBetween(1, 100),
+			},
+
 			"deregister_on_destroy": {
 				Description: "If true, the job will be deregistered on destroy.",
 				Optional:    true,
@@ -70,6 +89,12 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"preserve_across_namespace_change": {
+				Description: "If true, a change of the namespace of the job registers it in the new namespace before deregistering it from the previous one, instead of replacing the resource.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
 			"detach": {
 				Description: "If true, the provider will return immediately after creating or updating, instead of monitoring.",
 				Optional:    true,
@@ -89,6 +114,242 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeString,
 			},
 
+			"batch_exit_code": {
+				Description: "If detach = false and the job is a batch job, the exit code of each task, keyed by `<group>.<task>`, from its most recent allocation.",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+
+			"multiregion_deploy": {
+				Description: "Monitor the deployment of a multiregion job in each of its regions after creating or updating, instead of the local deployment monitored when detach = false.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ordered": {
+							Description: "If true, wait for the deployment in each region to be healthy and unblock it before monitoring the next region, in the order of the multiregion block.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"fail_fast": {
+							Description: "If true, fail the deployments in the remaining regions and return as soon as the deployment fails in a region.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+					},
+				},
+			},
+
+			"multiregion_deployment_status": {
+				Description: "If multiregion_deploy is set, the status of the deployment in each region associated with the last job create/update.",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"canary_status": {
+				Description: "The canary status of each task group with canaries in the latest deployment of the job.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"desired_canaries": {
+							Description: "The number of canaries the deployment wants.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"placed_canaries": {
+							Description: "The number of canaries placed by the deployment.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+						"healthy_canaries": {
+							Description: "The number of placed canaries that are healthy.",
+							Type:        schema.TypeInt,
+							Computed:    true,
+						},
+					},
+				},
+			},
+
+			"plan_annotations": {
+				Description: "The annotations of the Nomad plan of the last change to the job, including the desired updates of each task group and any placement warnings.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"warnings": {
+							Description: "The warnings returned by the Nomad plan.",
+							Type:        schema.TypeString,
+							Computed:    true,
+						},
+						"task_groups": {
+							Description: "The desired updates of each task group.",
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"group": {
+										Description: "The name of the task group.",
+										Type:        schema.TypeString,
+										Computed:    true,
+									},
+									"place": {
+										Description: "The number of allocations to place.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"stop": {
+										Description: "The number of allocations to stop.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"migrate": {
+										Description: "The number of allocations to migrate.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"ignore": {
+										Description: "The number of allocations left unchanged.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"in_place_update": {
+										Description: "The number of allocations updated in-place.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"destructive_update": {
+										Description: "The number of allocations replaced by a destructive update.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"canary": {
+										Description: "The number of canaries to place.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"preemptions": {
+										Description: "The number of allocations preempted.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+									"failed_placements": {
+										Description: "The number of allocations that could not be placed.",
+										Type:        schema.TypeInt,
+										Computed:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
+			"wait_for_running": {
+				Description: "Wait for allocations of the job to be running after creating or updating, regardless of deployments.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"count": {
+							Description: "The number of allocations that must be running.",
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     1,
+						},
+						"timeout": {
+							Description:  "How long to wait for the allocations to be running.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "2m",
+							ValidateFunc: validateDuration,
+						},
+					},
+				},
+			},
+
+			"promote": {
+				Description: "Promote the canaries of the deployment created by the last job create/update, for jobs using manual promotion.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"wait_for_canaries": {
+							Description: "If true, wait for the canaries to be healthy before promoting them. If false, the promotion fails unless the canaries are already healthy.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+						},
+						"timeout": {
+							Description:  "How long to wait for the canaries to be healthy.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "10m",
+							ValidateFunc: validateDuration,
+						},
+					},
+				},
+			},
+
+			"fail_on_auto_revert": {
+				Description: "If detach = false, fail with a distinct error when the deployment is auto-reverted to a previous version of the job.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"wait_for_checks": {
+				Description: "If detach = false, wait for the Nomad service checks of the allocations of the deployment to pass once it's healthy, and fail if any of them is still failing after checks_grace_period.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"checks_grace_period": {
+				Description:  "How long to wait for the checks to pass when wait_for_checks is set.",
+				Optional:     true,
+				Type:         schema.TypeString,
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+			},
+
+			"fail_on_placement_errors": {
+				Description: "If detach = false and the job is a batch job, fail when allocations can't be placed or any allocation fails.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"require_healthy": {
+				Description: "If detach = false, the number of healthy allocations each task group must reach in the deployment before the apply returns.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"count": {
+							Description:  "The number of healthy allocations the task group must reach.",
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+
 			"hcl2": {
 				Description: "Configuration for the HCL2 jobspec parser.",
 				Optional:    true,
@@ -114,10 +375,22 @@ func resourceJob() *schema.Resource {
 							Type:        schema.TypeMap,
 							Optional:    true,
 						},
+						"var_files": {
+							Description: "Paths of HCL2 variable files to use when templating the job. Values set in `vars` take precedence over the files.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
 					},
 				},
 			},
 
+			"hcl2_var_files_digest": {
+				Description: "The SHA-256 digest of the content of the HCL2 variable files, so changes to the files are shown in the plan.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
 			"hcl1": {
 				Description: "If true, the `jobspec` will be parsed using the HCL1 format.",
 				Optional:    true,
@@ -131,12 +404,49 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"create_index": {
+				Description: "The Raft index at which the job was created.",
+				Computed:    true,
+				Type:        schema.TypeString, // it's an int64, so won't fit in our TypeInt
+			},
+
 			"modify_index": {
 				Description: "Integer that increments for each change. Used to detect any changes between plan and apply.",
 				Computed:    true,
 				Type:        schema.TypeString, // it's an int64, so won't fit in our TypeInt
 			},
 
+			"submit_time": {
+				Description: "The time the current version of the job was submitted, in RFC3339 format.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"version_count": {
+				Description: "The number of versions of the job retained by Nomad.",
+				Computed:    true,
+				Type:        schema.TypeInt,
+			},
+
+			"effective_deadline": {
+				Description: "The longest deadline of the update strategies of the job task groups. It's used as the timeout to monitor deployments when no create or update timeout is set.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"previous_stable_version": {
+				Description: "The most recent stable version of the job before the current version, or -1 if there is none.",
+				Computed:    true,
+				Type:        schema.TypeInt,
+			},
+
+			"max_versions": {
+				Description:  "If set, warn when the number of versions of the job retained by Nomad exceeds this value.",
+				Optional:     true,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
 			"name": {
 				Description: "The name of the job, as derived from the jobspec.",
 				Computed:    true,
@@ -162,12 +472,50 @@ func resourceJob() *schema.Resource {
 				Default:     false,
 			},
 
+			"purge_before_rerun": {
+				Description: "If true, and rerun_if_dead is set, purges the dead job before registering it again.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+
+			"manage_count": {
+				Description: "If false, the provider preserves the current count of each task group when updating the job, so counts managed externally (for example by the autoscaler) are not reset.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+
+			"manage_groups": {
+				Description: "If set, only the listed task groups of the job are managed by this resource. The other task groups of the registered job are preserved on registration, so several configurations can manage different groups of the same job.",
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
 			"status": {
 				Description: "The status of the job.",
 				Computed:    true,
 				Type:        schema.TypeString,
 			},
 
+			"stopped": {
+				Description: "Whether the job is stopped, for example outside of Terraform. Stopped jobs are only registered again if rerun_if_dead is set.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"dispatched": {
+				Description: "Whether the job was dispatched from a parameterized job.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"parent_id": {
+				Description: "The ID of the parameterized or periodic job that created the job, if any.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
 			"region": {
 				Description: "The target region for the job, as derived from the jobspec.",
 				Computed:    true,
@@ -175,8 +523,10 @@ func resourceJob() *schema.Resource {
 			},
 
 			"datacenters": {
-				Description: "The target datacenters for the job, as derived from the jobspec.",
+				Description: "The target datacenters for the job. If set, replaces the datacenters of the jobspec. Defaults to the datacenters of the jobspec.",
+				Optional:    true,
 				Computed:    true,
+				MinItems:    1,
 				Type:        schema.TypeSet,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
@@ -191,6 +541,12 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"job_json": {
+				Description: "The job registered in Nomad, canonicalized and encoded as JSON, including the changes made by override arguments.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
 			"allocation_ids": {
 				Deprecated:  "Retrieving allocation IDs from the job resource is deprecated and will be removed in a future release. Use the nomad_allocations data source instead.",
 				Description: "The IDs for allocations associated with this job.",
@@ -203,60 +559,383 @@ func resourceJob() *schema.Resource {
 
 			"task_groups": taskGroupSchema(),
 
-			"purge_on_destroy": {
-				Description: "Whether to purge the job when the resource is destroyed.",
-				Optional:    true,
-				Type:        schema.TypeBool,
+			"parameterized": {
+				Description: "The parameterized job configuration, as derived from the jobspec.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"payload": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"meta_required": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"meta_optional": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
 			},
 
-			"consul_token": {
-				Description: "The Consul token used to submit this job.",
-				Optional:    true,
-				Sensitive:   true,
+			"periodic_next_run": {
+				Description: "The next time the periodic job is launched, in RFC3339 format.",
+				Computed:    true,
 				Type:        schema.TypeString,
 			},
 
-			"vault_token": {
-				Description: "The Vault token used to submit this job.",
-				Optional:    true,
-				Sensitive:   true,
+			"periodic_last_child_status": {
+				Description: "The status of the child job most recently launched by the periodic job.",
+				Computed:    true,
 				Type:        schema.TypeString,
 			},
-		},
-	}
-}
 
-const (
-	MonitoringEvaluation = "monitoring_evaluation"
-	EvaluationComplete   = "evaluation_complete"
-	MonitoringDeployment = "monitoring_deployment"
-	DeploymentSuccessful = "deployment_successful"
-)
+			"periodic": {
+				Description: "The periodic job configuration, as derived from the jobspec.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cron": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"prohibit_overlap": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"timezone": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"enabled": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+					},
+				},
+			},
 
-func taskGroupSchema() *schema.Schema {
-	return &schema.Schema{
-		Computed: true,
-		Type:     schema.TypeList,
-		Elem: &schema.Resource{
-			Schema: map[string]*schema.Schema{
-				"name": {
-					Computed: true,
-					Type:     schema.TypeString,
+			"env_override": {
+				Description: "Environment variables merged into a task of the job before it is registered.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"task": {
+							Description: "The name of the task.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"vars": {
+							Description: "The environment variables to set in the task.",
+							Type:        schema.TypeMap,
+							Required:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
 				},
-				"count": {
-					Computed: true,
-					Type:     schema.TypeInt,
+			},
+
+			"restart_override": {
+				Description: "Restart policy settings applied to a task group of the job before it is registered.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"attempts": {
+							Description:  "The number of restarts allowed in the interval. -1 keeps the value of the jobspec.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      -1,
+							ValidateFunc: validation.IntAtLeast(-1),
+						},
+						"interval": {
+							Description:  "The duration of the interval in which attempts are counted.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"delay": {
+							Description:  "The duration to wait before restarting a task.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"mode": {
+							Description:  "What to do once attempts are exhausted in the interval, either \"fail\" or \"delay\".",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"fail", "delay"}, false),
+						},
+					},
 				},
-				// "scaling": {
-				// 	Computed: true,
-				// 	Type:     schema.TypeList,
-				// 	MinItems: 0,
-				// 	MaxItems: 1,
-				// 	Elem:     scalingPolicySchema(),
-				// },
-				"task": {
-					Computed: true,
-					Type:     schema.TypeList,
+			},
+
+			"reschedule_override": {
+				Description: "Reschedule policy settings applied to a task group of the job before it is registered.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"attempts": {
+							Description:  "The number of reschedule attempts allowed in the interval. -1 keeps the value of the jobspec. Conflicts with unlimited.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      -1,
+							ValidateFunc: validation.IntAtLeast(-1),
+						},
+						"interval": {
+							Description:  "The duration of the interval in which attempts are counted.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"delay": {
+							Description:  "The duration to wait before rescheduling an allocation.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"delay_function": {
+							Description:  "The function used to compute the delay between attempts, either \"constant\", \"exponential\" or \"fibonacci\".",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice([]string{"constant", "exponential", "fibonacci"}, false),
+						},
+						"max_delay": {
+							Description:  "The maximum delay between attempts.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateDuration,
+						},
+						"unlimited": {
+							Description: "If true, allocations are rescheduled without limit. Conflicts with attempts.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+						},
+					},
+				},
+			},
+
+			"vault_override": {
+				Description: "Vault settings applied to a task of the job before it is registered.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group": {
+							Description: "The name of the task group.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"task": {
+							Description: "The name of the task.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"cluster": {
+							Description: "The Vault cluster to use.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"role": {
+							Description: "The Vault role used to derive the task token.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"policies": {
+							Description: "The Vault policies the task needs.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"namespace": {
+							Description: "The Vault namespace to use.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+
+			"purge_on_destroy": {
+				Description: "Whether to purge the job when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"force_destroy": {
+				Description: "If true, the active deployment of the job is failed and the job is purged, ignoring shutdown delays, when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"global_deregister": {
+				Description: "If true, a multiregion job is deregistered from all of its regions when the resource is destroyed, instead of only the region of the provider.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"wait_for_destroy": {
+				Description: "If true, the provider will wait for the job to be stopped, or purged if purge_on_destroy is set, when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"stop_timeout": {
+				Description:  "How long to wait for the job to be stopped when wait_for_destroy is set. Defaults to the delete timeout.",
+				Optional:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateDuration,
+			},
+
+			"restart_on": {
+				Description: "Restart the allocations of the job, without registering it again, when the Nomad variables used by its templates change.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"variable_paths": {
+							Description: "The paths of the variables, in the namespace of the job, whose changes restart the allocations.",
+							Type:        schema.TypeList,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+
+			"variable_modify_indexes": {
+				Description: "The modify index of each variable in restart_on, as of the last apply.",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"wait_for_deregister": {
+				Description: "If true, the provider will wait for the evaluation created by the deregistration of the job to complete when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"purge_children_on_destroy": {
+				Description: "If true, child jobs dispatched or launched by a parameterized or periodic job are also deregistered when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"consul_token": {
+				Description: "The Consul token used to submit this job.",
+				Optional:    true,
+				Sensitive:   true,
+				Type:        schema.TypeString,
+			},
+
+			"vault_token": {
+				Description:      "The Vault token used to submit this job.",
+				Optional:         true,
+				Sensitive:        true,
+				Type:             schema.TypeString,
+				DiffSuppressFunc: vaultTokenDiffSuppress,
+			},
+
+			"ignore_vault_token_diff": {
+				Description: "If true, changes to vault_token alone don't cause the job to be registered again. The new token is used the next time the job is registered.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"warn_on_no_eligible_nodes": {
+				Description: "If true, warn when none of the ready and eligible nodes match the datacenters, node pool and constraints of the job.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"fail_on_region_mismatch": {
+				Description: "If true, fail the plan when the jobspec sets a region other than the region of the provider.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"fail_on_no_eligible_nodes": {
+				Description: "If true, fail the plan when none of the ready and eligible nodes match the datacenters, node pool and constraints of the job.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"track_submission": {
+				Description: "If true, the jobspec submitted to Nomad is read back and compared with the configuration to detect changes made outside of Terraform. Defaults to the provider track_job_submissions setting.",
+				Optional:    true,
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+		},
+	}
+}
+
+const (
+	MonitoringEvaluation = "monitoring_evaluation"
+	EvaluationComplete   = "evaluation_complete"
+	MonitoringDeployment = "monitoring_deployment"
+	DeploymentSuccessful = "deployment_successful"
+	MonitoringAllocs     = "monitoring_allocations"
+	AllocsRunning        = "allocations_running"
+	AllocsComplete       = "allocations_complete"
+	MonitoringChecks     = "monitoring_checks"
+	ChecksPassing        = "checks_passing"
+	MonitoringCanaries   = "monitoring_canaries"
+	CanariesHealthy      = "canaries_healthy"
+	MonitoringDestroy    = "monitoring_destroy"
+	JobDestroyed         = "job_destroyed"
+)
+
+func taskGroupSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"count": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				// "scaling": {
+				// 	Computed: true,
+				// 	Type:     schema.TypeList,
+				// 	MinItems: 0,
+				// 	MaxItems: 1,
+				// 	Elem:     scalingPolicySchema(),
+				// },
+				"task": {
+					Computed: true,
+					Type:     schema.TypeList,
 					Elem: &schema.Resource{
 						Schema: map[string]*schema.Schema{
 							"name": {
@@ -271,6 +950,10 @@ func taskGroupSchema() *schema.Schema {
 								Computed: true,
 								Type:     schema.TypeMap,
 							},
+							"env": {
+								Computed: true,
+								Type:     schema.TypeMap,
+							},
 							// "scaling": {
 							// 	Computed: true,
 							// 	Type:     schema.TypeList,
@@ -296,88 +979,3481 @@ func taskGroupSchema() *schema.Schema {
 									},
 								},
 							},
-						},
-					},
-				},
-				"volumes": {
-					Computed: true,
-					Type:     schema.TypeList,
-					Elem: &schema.Resource{
-						Schema: map[string]*schema.Schema{
-							"name": {
+							"vault": {
 								Computed: true,
-								Type:     schema.TypeString,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"cluster": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"role": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"policies": {
+											Computed: true,
+											Type:     schema.TypeList,
+											Elem:     &schema.Schema{Type: schema.TypeString},
+										},
+										"namespace": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+									},
+								},
 							},
-							"type": {
+							"service": jobServiceSchema(),
+							"logs": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"max_files": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"max_file_size": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"disabled": {
+											Computed: true,
+											Type:     schema.TypeBool,
+										},
+									},
+								},
+							},
+							"template": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"destination": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"vault_grace": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"perms": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"uid": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"gid": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"wait": {
+											Computed: true,
+											Type:     schema.TypeList,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{
+													"min": {
+														Computed: true,
+														Type:     schema.TypeString,
+													},
+													"max": {
+														Computed: true,
+														Type:     schema.TypeString,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							"artifact": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"source": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"destination": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"mode": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"insecure": {
+											Computed: true,
+											Type:     schema.TypeBool,
+										},
+										"options": {
+											Computed: true,
+											Type:     schema.TypeMap,
+											Elem:     &schema.Schema{Type: schema.TypeString},
+										},
+										"headers": {
+											Computed: true,
+											Type:     schema.TypeMap,
+											Elem:     &schema.Schema{Type: schema.TypeString},
+										},
+									},
+								},
+							},
+							"restart_policy": jobRestartPolicySchema(),
+							"kill_timeout": {
 								Computed: true,
 								Type:     schema.TypeString,
 							},
-							"read_only": {
+							"kill_signal": {
 								Computed: true,
-								Type:     schema.TypeBool,
+								Type:     schema.TypeString,
 							},
-							"source": {
+							"shutdown_delay": {
 								Computed: true,
 								Type:     schema.TypeString,
 							},
-						},
-					},
-				},
-				"meta": {
-					Computed: true,
-					Type:     schema.TypeMap,
-				},
-			},
-		},
-	}
-}
-
-// JobParserConfig stores configuration options for how to parse the jobspec.
-type JobParserConfig struct {
-	JSON JSONJobParserConfig
-	HCL1 HCL1JobParserConfig
-	HCL2 HCL2JobParserConfig
+							"csi_plugin": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"id": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"type": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"mount_dir": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"stage_publish_base_dir": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"health_timeout": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+									},
+								},
+							},
+							"lifecycle": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"hook": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"sidecar": {
+											Computed: true,
+											Type:     schema.TypeBool,
+										},
+									},
+								},
+							},
+							"schedule": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"cron": {
+											Computed: true,
+											Type:     schema.TypeList,
+											Elem: &schema.Resource{
+												Schema: map[string]*schema.Schema{
+													"start": {
+														Computed: true,
+														Type:     schema.TypeString,
+													},
+													"end": {
+														Computed: true,
+														Type:     schema.TypeString,
+													},
+													"timezone": {
+														Computed: true,
+														Type:     schema.TypeString,
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"service": jobServiceSchema(),
+				"volumes": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"type": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"read_only": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+							"source": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"per_alloc": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+							"access_mode": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"attachment_mode": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+						},
+					},
+				},
+				"shutdown_delay": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"consul": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"namespace": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"cluster": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"partition": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+						},
+					},
+				},
+				"ephemeral_disk": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"size": {
+								Computed: true,
+								Type:     schema.TypeInt,
+							},
+							"migrate": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+							"sticky": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+						},
+					},
+				},
+				"network": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"mode": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"cni_network": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"port": {
+								Computed: true,
+								Type:     schema.TypeList,
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"label": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+										"static": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"to": {
+											Computed: true,
+											Type:     schema.TypeInt,
+										},
+										"host_network": {
+											Computed: true,
+											Type:     schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"restart_policy": jobRestartPolicySchema(),
+				"reschedule_policy": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"attempts": {
+								Computed: true,
+								Type:     schema.TypeInt,
+							},
+							"interval": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"delay": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"delay_function": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"max_delay": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"unlimited": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+						},
+					},
+				},
+				"meta": {
+					Computed: true,
+					Type:     schema.TypeMap,
+				},
+			},
+		},
+	}
+}
+
+// jobServiceSchema returns the schema of the services summarized in
+// task_groups, for both group and task services.
+func jobRestartPolicySchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"attempts": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"interval": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"delay": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"mode": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func jobServiceSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"provider": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"port": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"cluster": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"tagged_addresses": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"on_update": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"check": jobServiceCheckSchema(),
+			},
+		},
+	}
+}
+
+func jobServiceCheckSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"type": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"command": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"args": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"path": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"protocol": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"port": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"expose": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+				"address_mode": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"interval": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"timeout": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"initial_status": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"tls_server_name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"tls_skip_verify": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+				"method": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"body": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"header": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"grpc_service": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"grpc_use_tls": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+				"task": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"success_before_passing": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"failures_before_critical": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"failures_before_warning": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"on_update": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"check_restart": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"limit": {
+								Computed: true,
+								Type:     schema.TypeInt,
+							},
+							"grace": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"ignore_warnings": {
+								Computed: true,
+								Type:     schema.TypeBool,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// JobParserConfig stores configuration options for how to parse the jobspec.
+type JobParserConfig struct {
+	JSON JSONJobParserConfig
+	HCL1 HCL1JobParserConfig
+	HCL2 HCL2JobParserConfig
+}
+
+// JSONJobParserConfig stores configuration options for the JSON jobspec parser.
+type JSONJobParserConfig struct {
+	Enabled bool
+}
+
+// HCL1JobParserConfig stores configuration options for the HCL1 jobspec parser.
+type HCL1JobParserConfig struct {
+	Enabled bool
+}
+
+// HCL2JobParserConfig stores configuration options for the HCL2 jobspec parser.
+type HCL2JobParserConfig struct {
+	AllowFS  bool
+	Vars     map[string]string
+	VarFiles []string
+
+	// Deprecated: Starting in v2.0.0 the provider assumes HCL2 parsing by
+	// default. This field should only be used to update the `hcl2` attribute
+	// in state without causing a diff.
+	Enabled bool
+}
+
+// ResourceFieldGetter are able to retrieve field values.
+// Examples: *schema.ResourceData and *schema.ResourceDiff
+type ResourceFieldGetter interface {
+	Get(string) interface{}
+}
+
+// ResourceConfigGetter is a ResourceFieldGetter that also gives access to
+// the raw configuration of the resource.
+type ResourceConfigGetter interface {
+	ResourceFieldGetter
+	GetRawConfig() cty.Value
+}
+
+// resourceJobApply registers the job and, for system jobs, warns about the
+// number of nodes it targets.
+func resourceJobApply(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Changes to the variables used by the job only restart its allocations.
+	if !d.IsNewResource() && !d.HasChangesExcept("restart_on", "variable_modify_indexes") {
+		return diag.FromErr(resourceJobRestartOnVariableChange(d, meta))
+	}
+
+	// A job moved to another namespace is only deregistered from the previous
+	// namespace once it's registered in the new one.
+	previousID := d.Id()
+	previousNamespace := ""
+	if !d.IsNewResource() && d.HasChange("namespace") {
+		oldNamespace, _ := d.GetChange("namespace")
+		previousNamespace = oldNamespace.(string)
+	}
+
+	diags, err := resourceJobRegister(d, meta)
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if previousNamespace != "" {
+		if err := deregisterMovedJob(d, meta, previousID, previousNamespace); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+	if err := resourceJobSetVariableModifyIndexes(d, meta); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	diags = append(diags, systemJobTargetNodesDiags(d, meta)...)
+	if warning := jobVersionCountWarning(d.Id(), d.Get("version_count").(int), d.Get("max_versions").(int)); warning != nil {
+		diags = append(diags, *warning)
+	}
+	if d.Get("warn_on_no_eligible_nodes").(bool) {
+		diags = append(diags, jobNoEligibleNodesDiags(d, meta)...)
+	}
+	return diags
+}
+
+// resourceJobRegister registers the job and returns warnings about its
+// configuration.
+func resourceJobRegister(d *schema.ResourceData, meta interface{}) (diag.Diagnostics, error) {
+	timeoutKey := schema.TimeoutCreate
+	if !d.IsNewResource() {
+		timeoutKey = schema.TimeoutUpdate
+		d.Partial(true)
+	}
+	timeout := d.Timeout(timeoutKey)
+
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	// Get the jobspec itself.
+	jobspecRaw := d.Get("jobspec").(string)
+
+	// Read job parsing config.
+	jobParserConfig, err := parseJobParserConfig(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use consul token declared on resource, if present.
+	consulToken := d.Get("consul_token").(string)
+	if consulToken == "" {
+		consulToken = *providerConfig.consulToken
+	}
+
+	// Use vault token declared on resource, if present.
+	vaultToken := resourceJobVaultToken(d)
+	if vaultToken == "" {
+		vaultToken = *providerConfig.vaultToken
+	}
+
+	// Parse jobspec.
+	job, err := parseJobspec(jobspecRaw, jobParserConfig, &vaultToken, &consulToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyJobOverrides(job, d); err != nil {
+		return nil, err
+	}
+	applyProviderJobDefaults(job, providerConfig)
+	if err := validateJobConsulPartitions(job, providerConfig); err != nil {
+		return nil, err
+	}
+	if err := validateJobTaskResources(job); err != nil {
+		return nil, err
+	}
+	var warnings diag.Diagnostics
+	if _, ok := parseJobspecForWarnings(jobspecRaw); !ok {
+		warnings = jobspecStaticWarnings(job)
+	}
+	if warning := jobRegionWarning(providerConfig, job); warning != nil {
+		warnings = append(warnings, *warning)
+	}
+
+	if job.Namespace == nil || *job.Namespace == "" {
+		defaultNamespace := "default"
+		job.Namespace = &defaultNamespace
+	}
+
+	if !d.Get("manage_count").(bool) {
+		oldSpecRaw, _ := d.GetChange("jobspec")
+		previous := parsePreviousJobspec(oldSpecRaw.(string), jobParserConfig)
+		if err := preserveTaskGroupCounts(client, job, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	if managed := resourceJobManagedGroups(d); len(managed) > 0 {
+		if err := mergeManagedTaskGroups(client, job, managed); err != nil {
+			return nil, err
+		}
+	}
+
+	jobJSON, err := canonicalJobJSON(job)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a configured timeout, deployments are monitored for as long as
+	// the deadlines of the job allow.
+	deadline := jobEffectiveDeadline(job)
+	if deadline > 0 && !resourceJobTimeoutConfigured(d, timeoutKey) {
+		timeout = deadline + deploymentDeadlineMargin
+		log.Printf("[DEBUG] monitoring job %q for up to %s based on its update deadlines", *job.ID, timeout)
+	}
+
+	// Register the job
+	wantModifyIndexStrI, _ := d.GetChange("modify_index")
+	wantModifyIndex, err := strconv.ParseUint(wantModifyIndexStrI.(string), 10, 64)
+	if err != nil {
+		wantModifyIndex = 0
+	}
+
+	// Purge the dead job being run again so it is registered from scratch.
+	if oldStatus, _ := d.GetChange("status"); !d.IsNewResource() && oldStatus.(string) == "dead" &&
+		d.Get("rerun_if_dead").(bool) && d.Get("purge_before_rerun").(bool) {
+		if err := purgeDeadJob(client, timeout, *job.Namespace, d.Id()); err != nil {
+			return nil, err
+		}
+		wantModifyIndex = 0
+	}
+
+	sub := &api.JobSubmission{
+		Source:        jobspecRaw,
+		Format:        "hcl2",
+		VariableFlags: jobParserConfig.HCL2.Vars,
+	}
+	switch {
+	case jobParserConfig.JSON.Enabled:
+		sub.Format = "json"
+	case jobParserConfig.HCL1.Enabled:
+		sub.Format = "hcl1"
+	default:
+		sub.Variables, err = readHCL2VarFiles(jobParserConfig.HCL2.VarFiles)
+		if err != nil {
+			return nil, err
+		}
+		d.Set("hcl2_var_files_digest", hcl2VarFilesDigest(sub.Variables))
+	}
+
+	resp, _, err := client.Jobs().RegisterOpts(job, &api.RegisterOptions{
+		PolicyOverride: d.Get("policy_override").(bool),
+		ModifyIndex:    wantModifyIndex,
+		Submission:     sub,
+		EvalPriority:   d.Get("eval_priority").(int),
+	}, &api.WriteOptions{
+		Namespace: *job.Namespace,
+	})
+	if err != nil {
+		if msg := jobSentinelRejection(client, *job.Namespace, err); msg != "" {
+			return nil, fmt.Errorf("error applying jobspec: %s\n\n%s", msg, err)
+		}
+		return nil, fmt.Errorf("error applying jobspec: %s", err)
+	}
+
+	if !d.IsNewResource() {
+		d.Partial(false)
+	}
+
+	log.Printf("[DEBUG] job '%s' registered in namespace '%s'", *job.ID, *job.Namespace)
+	d.SetId(*job.ID)
+	d.Set("name", job.ID)
+	d.Set("namespace", job.Namespace)
+	d.Set("modify_index", strconv.FormatUint(resp.JobModifyIndex, 10))
+	d.Set("job_json", jobJSON)
+	d.Set("effective_deadline", effectiveDeadlineRaw(deadline))
+
+	// Canaries are promoted before monitoring the deployment, since it isn't
+	// successful until they are.
+	if promote, ok := d.GetOk("promote"); ok && resp.EvalID != "" {
+		promoteConfig := promote.([]interface{})[0].(map[string]interface{})
+		promoteTimeout, err := time.ParseDuration(promoteConfig["timeout"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid promote timeout: %s", err)
+		}
+
+		log.Printf("[DEBUG] will promote canaries of job '%s' in namespace '%s'", *job.ID, *job.Namespace)
+		err = promoteDeploymentCanaries(client, promoteTimeout, *job.Namespace, resp.EvalID, promoteConfig["wait_for_canaries"].(bool))
+		if err != nil {
+			return nil, fmt.Errorf("error promoting canaries of job '%s': %s", *job.ID, err)
+		}
+	}
+
+	batchCompleted := false
+	if multiregionDeploy, ok := d.GetOk("multiregion_deploy"); ok {
+		if job.Multiregion == nil || len(job.Multiregion.Regions) == 0 {
+			return nil, fmt.Errorf("multiregion_deploy is set, but job '%s' doesn't have a multiregion block", *job.ID)
+		}
+
+		deployConfig := multiregionDeploy.([]interface{})[0].(map[string]interface{})
+		regions := make([]string, 0, len(job.Multiregion.Regions))
+		for _, region := range job.Multiregion.Regions {
+			regions = append(regions, region.Name)
+		}
+
+		log.Printf("[DEBUG] will monitor multiregion deployment of job '%s' in namespace '%s'", *job.ID, *job.Namespace)
+		statuses, err := monitorMultiregionDeployment(client, timeout, *job.Namespace, *job.ID, regions,
+			deployConfig["ordered"].(bool), deployConfig["fail_fast"].(bool))
+		d.Set("multiregion_deployment_status", statuses)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error waiting for job '%s' to deploy successfully in all regions: %s",
+				*job.ID, err)
+		}
+	} else if d.Get("detach") == false && resp.EvalID != "" {
+		log.Printf("[DEBUG] will monitor scheduling/deployment of job '%s' in namespace '%s'", *job.ID, *job.Namespace)
+		requiredHealthy := map[string]int{}
+		for _, raw := range d.Get("require_healthy").([]interface{}) {
+			req := raw.(map[string]interface{})
+			requiredHealthy[req["group"].(string)] = req["count"].(int)
+		}
+
+		failOnAutoRevert := d.Get("fail_on_auto_revert").(bool)
+		deployment, err := monitorDeployment(client, timeout, *job.Namespace, resp.EvalID, requiredHealthy, failOnAutoRevert)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error waiting for job '%s' to schedule/deploy successfully: %s",
+				*job.ID, err)
+		}
+		if deployment != nil {
+			d.Set("deployment_id", deployment.ID)
+			d.Set("deployment_status", deployment.Status)
+
+			// Nomad services aren't registered in Consul, so verify their
+			// registrations and checks using the Nomad services API.
+			if services := nomadServiceNames(job); len(services) > 0 {
+				if err := checkNomadServices(client, *job.Namespace, deployment.ID, services, checksHealthGroups(job), ignoredHealthChecks(job)); err != nil {
+					return nil, fmt.Errorf("error checking Nomad services of job '%s': %s", *job.ID, err)
+				}
+			}
+
+			if d.Get("wait_for_checks").(bool) {
+				gracePeriod, err := time.ParseDuration(d.Get("checks_grace_period").(string))
+				if err != nil {
+					return nil, fmt.Errorf("invalid checks_grace_period: %s", err)
+				}
+
+				log.Printf("[DEBUG] waiting for the checks of job '%s' in namespace '%s' to pass", *job.ID, *job.Namespace)
+				if err := monitorAllocationChecks(client, gracePeriod, *job.Namespace, deployment.ID, ignoredHealthChecks(job)); err != nil {
+					return nil, fmt.Errorf("error waiting for checks of job '%s' to pass: %s", *job.ID, err)
+				}
+			}
+		} else {
+			d.Set("deployment_id", nil)
+			d.Set("deployment_status", nil)
+		}
+
+		// Batch jobs don't have deployments, so wait for their allocations
+		// to run to completion instead.
+		if job.Type != nil && *job.Type == api.JobTypeBatch {
+			if err := monitorBatchJob(d, client, timeout, *job.Namespace, *job.ID, resp.EvalID, resp.JobModifyIndex); err != nil {
+				return nil, err
+			}
+			batchCompleted = true
+		}
+	}
+
+	// The allocations of a monitored batch job have already run to
+	// completion, so they can't be waited for to be running.
+	if waitForRunning, ok := d.GetOk("wait_for_running"); ok && batchCompleted {
+		log.Printf("[DEBUG] skipping wait_for_running for batch job '%s' in namespace '%s' since its allocations completed", *job.ID, *job.Namespace)
+	} else if ok {
+		waitConfig := waitForRunning.([]interface{})[0].(map[string]interface{})
+		count := waitConfig["count"].(int)
+		waitTimeout, err := time.ParseDuration(waitConfig["timeout"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid wait_for_running timeout: %s", err)
+		}
+
+		log.Printf("[DEBUG] waiting for %d allocations of job '%s' in namespace '%s' to be running", count, *job.ID, *job.Namespace)
+		err = monitorAllocationsRunning(client, waitTimeout, *job.Namespace, *job.ID, resp.JobModifyIndex, count)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error waiting for job '%s' allocations to be running: %s",
+				*job.ID, err)
+		}
+	}
+
+	return warnings, resourceJobRead(d, meta) // populate other computed attributes
+}
+
+// vaultTokenDiffSuppress suppresses changes to the vault_token of an existing
+// job when ignore_vault_token_diff is set, so rotating the token doesn't
+// register the job again.
+func vaultTokenDiffSuppress(_, old, _ string, d *schema.ResourceData) bool {
+	return d.Id() != "" && old != "" && d.Get("ignore_vault_token_diff").(bool)
+}
+
+// resourceJobVaultToken returns the Vault token declared on the resource.
+// Changes to vault_token may be suppressed by vaultTokenDiffSuppress, so the
+// token is read from the configuration to make sure the latest one is used
+// when the job is registered.
+func resourceJobVaultToken(d *schema.ResourceData) string {
+	raw := d.GetRawConfig()
+	if !raw.IsKnown() || raw.IsNull() {
+		return d.Get("vault_token").(string)
+	}
+
+	token := raw.GetAttr("vault_token")
+	if !token.IsKnown() || token.IsNull() {
+		return ""
+	}
+	return token.AsString()
+}
+
+// parsePreviousJobspec parses the jobspec the job was last registered with,
+// returning nil if there isn't one or it can't be parsed.
+func parsePreviousJobspec(raw string, config JobParserConfig) *api.Job {
+	if raw == "" {
+		return nil
+	}
+	job, err := parseJobspec(raw, config, nil, nil)
+	if err != nil {
+		log.Printf("[WARN] failed to parse previous jobspec: %s", err)
+		return nil
+	}
+	return job
+}
+
+// resourceJobManagedGroups returns the task groups listed in manage_groups,
+// or nil if the resource manages the whole job.
+func resourceJobManagedGroups(d ResourceFieldGetter) []string {
+	raw, ok := d.Get("manage_groups").(*schema.Set)
+	if !ok || raw.Len() == 0 {
+		return nil
+	}
+	groups := make([]string, 0, raw.Len())
+	for _, g := range raw.List() {
+		groups = append(groups, g.(string))
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// validateManagedTaskGroups returns an error if the jobspec defines a task
+// group that isn't listed in manage_groups, since it would otherwise be
+// silently replaced by, or removed in favor of, the registered group.
+func validateManagedTaskGroups(job *api.Job, managed []string) error {
+	for _, tg := range job.TaskGroups {
+		if tg.Name == nil {
+			continue
+		}
+		if !slices.Contains(managed, *tg.Name) {
+			return fmt.Errorf("task group %q is defined in the jobspec but not listed in manage_groups", *tg.Name)
+		}
+	}
+	return nil
+}
+
+// mergeManagedTaskGroups adds the task groups of the registered job that are
+// not listed in managed to job, so registering job doesn't remove them. Jobs
+// that are not registered yet are left unchanged.
+func mergeManagedTaskGroups(client *api.Client, job *api.Job, managed []string) error {
+	if err := validateManagedTaskGroups(job, managed); err != nil {
+		return err
+	}
+
+	current, _, err := client.Jobs().Info(*job.ID, &api.QueryOptions{
+		Namespace: *job.Namespace,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil
+		}
+		return fmt.Errorf("error reading job %q to merge its task groups: %s", *job.ID, err)
+	}
+
+	job.TaskGroups = mergeTaskGroups(current.TaskGroups, job.TaskGroups, managed)
+	return nil
+}
+
+// mergeTaskGroups returns the task groups of current, with the groups listed
+// in managed replaced by the group of the same name in desired, or removed if
+// desired doesn't define it. The groups of desired that are not in current
+// are appended in order.
+func mergeTaskGroups(current, desired []*api.TaskGroup, managed []string) []*api.TaskGroup {
+	byName := make(map[string]*api.TaskGroup, len(desired))
+	for _, tg := range desired {
+		if tg.Name != nil {
+			byName[*tg.Name] = tg
+		}
+	}
+
+	merged := make([]*api.TaskGroup, 0, len(current)+len(desired))
+	seen := make(map[string]bool, len(current))
+	for _, tg := range current {
+		if tg.Name == nil {
+			continue
+		}
+		seen[*tg.Name] = true
+		if !slices.Contains(managed, *tg.Name) {
+			merged = append(merged, tg)
+			continue
+		}
+		if desiredTG, ok := byName[*tg.Name]; ok {
+			merged = append(merged, desiredTG)
+		} else {
+			log.Printf("[DEBUG] managed task group %q was removed from the jobspec, it will be removed from the job", *tg.Name)
+		}
+	}
+	for _, tg := range desired {
+		if tg.Name != nil && !seen[*tg.Name] {
+			merged = append(merged, tg)
+		}
+	}
+	return merged
+}
+
+// removeManagedTaskGroups registers the job without the task groups listed in
+// managed, and returns whether it did so. It returns false if the job doesn't
+// exist or has no other task group, in which case the job should be
+// deregistered instead.
+func removeManagedTaskGroups(client *api.Client, namespace, id string, managed []string, policyOverride bool) (bool, error) {
+	current, _, err := client.Jobs().Info(id, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading job %q to remove its managed task groups: %s", id, err)
+	}
+
+	remaining := mergeTaskGroups(current.TaskGroups, nil, managed)
+	if len(remaining) == 0 {
+		return false, nil
+	}
+
+	log.Printf("[DEBUG] removing task groups %v from job %q, %d other task groups remain", managed, id, len(remaining))
+	current.TaskGroups = remaining
+	_, _, err = client.Jobs().RegisterOpts(current, &api.RegisterOptions{
+		EnforceIndex:   true,
+		ModifyIndex:    *current.JobModifyIndex,
+		PolicyOverride: policyOverride,
+	}, &api.WriteOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error removing managed task groups from job %q: %s", id, err)
+	}
+	return true, nil
+}
+
+// preserveTaskGroupCounts rewrites the count of each task group in job to
+// match the count of the registered job, so counts changed outside of
+// Terraform are not reset on registration. Groups that are not registered yet
+// keep the count from the jobspec, and so do groups whose count in the
+// jobspec changed since the previous jobspec, since that change is intended.
+func preserveTaskGroupCounts(client *api.Client, job *api.Job, previous *api.Job) error {
+	current, _, err := client.Jobs().Info(*job.ID, &api.QueryOptions{
+		Namespace: *job.Namespace,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil
+		}
+		return fmt.Errorf("error reading job %q to preserve task group counts: %s", *job.ID, err)
+	}
+
+	setTaskGroupCounts(job, current, previous)
+	return nil
+}
+
+// setTaskGroupCounts copies the count of each task group in current into the
+// task group of the same name in job, except for the groups whose count in
+// job differs from their count in previous. previous may be nil.
+func setTaskGroupCounts(job *api.Job, current *api.Job, previous *api.Job) {
+	counts := taskGroupCounts(current)
+	declared := taskGroupCounts(previous)
+
+	for _, tg := range job.TaskGroups {
+		if tg.Name == nil {
+			continue
+		}
+		if previous != nil && !reflect.DeepEqual(declared[*tg.Name], tg.Count) {
+			log.Printf("[DEBUG] count of task group %q changed in the jobspec, it won't be preserved", *tg.Name)
+			continue
+		}
+		if count, ok := counts[*tg.Name]; ok && count != nil {
+			tg.Count = pointer.Of(*count)
+		}
+	}
+}
+
+// taskGroupCounts returns the count of each task group of the job by name.
+func taskGroupCounts(job *api.Job) map[string]*int {
+	if job == nil {
+		return nil
+	}
+	counts := make(map[string]*int, len(job.TaskGroups))
+	for _, tg := range job.TaskGroups {
+		if tg.Name != nil {
+			counts[*tg.Name] = tg.Count
+		}
+	}
+	return counts
+}
+
+// monitorAllocationsRunning waits until at least count allocations of the
+// version of the job registered at jobModifyIndex are running, independently
+// of any deployment. Allocations of previous versions that are still running
+// during an update aren't counted.
+func monitorAllocationsRunning(client *api.Client, timeout time.Duration, namespace string, jobID string, jobModifyIndex uint64, count int) error {
+	version, err := jobVersionAtIndex(client, namespace, jobID, jobModifyIndex)
+	if err != nil {
+		return err
+	}
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{MonitoringAllocs},
+		Target:     []string{AllocsRunning},
+		Refresh:    allocationsRunningStateRefreshFunc(client, namespace, jobID, version, count),
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err = stateConf.WaitForState()
+	return err
+}
+
+// jobVersionAtIndex returns the version of the job registered at
+// jobModifyIndex. Registering a job without changes doesn't create a new
+// version, in which case Nomad returns the index of the current version.
+func jobVersionAtIndex(client *api.Client, namespace string, jobID string, jobModifyIndex uint64) (uint64, error) {
+	versions, _, _, err := client.Jobs().Versions(jobID, false, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error reading versions of job '%s': %s", jobID, err)
+	}
+	for _, v := range versions {
+		if v.JobModifyIndex != nil && *v.JobModifyIndex == jobModifyIndex && v.Version != nil {
+			return *v.Version, nil
+		}
+	}
+	return 0, fmt.Errorf("no version of job '%s' registered at index %d", jobID, jobModifyIndex)
+}
+
+// allocationsRunningStateRefreshFunc returns a resource.StateRefreshFunc that
+// is used to watch the number of running allocations of a job version.
+func allocationsRunningStateRefreshFunc(client *api.Client, namespace string, jobID string, version uint64, count int) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		allocs, _, err := client.Jobs().Allocations(jobID, false, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			log.Printf("[ERROR] error on Job.Allocations during allocationsRunningStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		running := runningAllocations(allocs, version)
+		log.Printf("[DEBUG] job '%s' in namespace '%s' has %d/%d allocations of version %d running", jobID, namespace, running, count, version)
+		if running >= count {
+			return allocs, AllocsRunning, nil
+		}
+		return allocs, MonitoringAllocs, nil
+	}
+}
+
+// runningAllocations returns the number of running allocations of the job
+// version that aren't being stopped. Allocations updated in place are
+// counted, since Nomad sets their job version to the new one.
+func runningAllocations(allocs []*api.AllocationListStub, version uint64) int {
+	running := 0
+	for _, alloc := range allocs {
+		if alloc.JobVersion != version || alloc.DesiredStatus != api.AllocDesiredStatusRun {
+			continue
+		}
+		if alloc.ClientStatus == api.AllocClientStatusRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// monitorBatchJob waits for the allocations of a batch job created by the
+// registration at jobModifyIndex to complete, and sets the exit codes of its
+// tasks. Placement and allocation failures fail the apply if
+// fail_on_placement_errors is set.
+func monitorBatchJob(d *schema.ResourceData, client *api.Client, timeout time.Duration, namespace string, jobID string, evalID string, jobModifyIndex uint64) error {
+	failOnErrors := d.Get("fail_on_placement_errors").(bool)
+
+	eval, _, err := client.Evaluations().Info(evalID, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading evaluation '%s': %s", evalID, err)
+	}
+	if len(eval.FailedTGAllocs) > 0 {
+		groups := make([]string, 0, len(eval.FailedTGAllocs))
+		for group := range eval.FailedTGAllocs {
+			groups = append(groups, group)
+		}
+		sort.Strings(groups)
+		if failOnErrors {
+			return fmt.Errorf("failed to place allocations of batch job '%s' for task groups: %s",
+				jobID, strings.Join(groups, ", "))
+		}
+		log.Printf("[WARN] failed to place allocations of batch job '%s' for task groups: %s",
+			jobID, strings.Join(groups, ", "))
+	}
+
+	log.Printf("[DEBUG] waiting for allocations of batch job '%s' in namespace '%s' to complete", jobID, namespace)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{MonitoringAllocs},
+		Target:     []string{AllocsComplete},
+		Refresh:    batchAllocationsStateRefreshFunc(client, namespace, jobID, jobModifyIndex),
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	state, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("error waiting for allocations of batch job '%s' to complete: %s", jobID, err)
+	}
+
+	allocs := state.([]*api.AllocationListStub)
+	d.Set("batch_exit_code", batchExitCodes(allocs))
+
+	var failed []string
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == api.AllocClientStatusFailed {
+			failed = append(failed, alloc.ID)
+		}
+	}
+	if len(failed) > 0 {
+		if failOnErrors {
+			return fmt.Errorf("allocations of batch job '%s' failed: %s", jobID, strings.Join(failed, ", "))
+		}
+		log.Printf("[WARN] allocations of batch job '%s' failed: %s", jobID, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// batchAllocationsStateRefreshFunc returns a resource.StateRefreshFunc that
+// is used to watch the allocations of a batch job until they all complete.
+func batchAllocationsStateRefreshFunc(client *api.Client, namespace string, jobID string, jobModifyIndex uint64) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		allocs, _, err := client.Jobs().Allocations(jobID, false, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			log.Printf("[ERROR] error on Job.Allocations during batchAllocationsStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		allocs = batchAllocations(allocs, jobModifyIndex)
+		pending := 0
+		for _, alloc := range allocs {
+			if !batchAllocationDone(alloc) {
+				pending++
+			}
+		}
+
+		log.Printf("[DEBUG] batch job '%s' in namespace '%s' has %d/%d allocations pending", jobID, namespace, pending, len(allocs))
+		if pending == 0 {
+			return allocs, AllocsComplete, nil
+		}
+		return allocs, MonitoringAllocs, nil
+	}
+}
+
+// batchAllocations returns the allocations created after the job was
+// registered at jobModifyIndex, leaving out the failed allocations that were
+// replaced by a rescheduled allocation.
+func batchAllocations(allocs []*api.AllocationListStub, jobModifyIndex uint64) []*api.AllocationListStub {
+	ret := make([]*api.AllocationListStub, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.CreateIndex <= jobModifyIndex || alloc.NextAllocation != "" {
+			continue
+		}
+		ret = append(ret, alloc)
+	}
+	return ret
+}
+
+// batchAllocationDone returns whether the allocation of a batch job has run
+// to completion. Failed allocations waiting to be rescheduled are not done.
+func batchAllocationDone(alloc *api.AllocationListStub) bool {
+	switch alloc.ClientStatus {
+	case api.AllocClientStatusComplete, api.AllocClientStatusLost:
+		return true
+	case api.AllocClientStatusFailed:
+		return alloc.FollowupEvalID == ""
+	}
+	return false
+}
+
+// batchExitCodes returns the exit code of the tasks of the allocations, keyed
+// by "<group>.<task>", from the most recently created allocation.
+func batchExitCodes(allocs []*api.AllocationListStub) map[string]int {
+	sorted := slices.Clone(allocs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreateIndex < sorted[j].CreateIndex
+	})
+
+	codes := make(map[string]int)
+	for _, alloc := range sorted {
+		for task, state := range alloc.TaskStates {
+			if code, ok := taskExitCode(state); ok {
+				codes[alloc.TaskGroup+"."+task] = code
+			}
+		}
+	}
+	return codes
+}
+
+// taskExitCode returns the exit code of the last time the task terminated.
+func taskExitCode(state *api.TaskState) (int, bool) {
+	if state == nil {
+		return 0, false
+	}
+	for i := len(state.Events) - 1; i >= 0; i-- {
+		event := state.Events[i]
+		if event == nil || event.Type != api.TaskTerminated {
+			continue
+		}
+		if raw, ok := event.Details["exit_code"]; ok {
+			if code, err := strconv.Atoi(raw); err == nil {
+				return code, true
+			}
+		}
+		return event.ExitCode, true
+	}
+	return 0, false
+}
+
+// promoteDeploymentCanaries waits for the evaluation of a job create/update
+// and promotes the canaries of the deployment it creates, if any. If
+// waitForHealthy is set, the canaries are promoted once they are all healthy.
+func promoteDeploymentCanaries(client *api.Client, timeout time.Duration, namespace string, evalID string, waitForHealthy bool) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{MonitoringEvaluation},
+		Target:     []string{EvaluationComplete},
+		Refresh:    evaluationStateRefreshFunc(client, namespace, evalID),
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	state, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("error waiting for evaluation: %s", err)
+	}
+
+	deploymentID := state.(*api.Evaluation).DeploymentID
+	if deploymentID == "" {
+		log.Printf("[WARN] job has been scheduled, but there is no deployment to promote")
+		return nil
+	}
+
+	opts := &api.QueryOptions{Namespace: namespace}
+	deployment, _, err := client.Deployments().Info(deploymentID, opts)
+	if err != nil {
+		return fmt.Errorf("error reading deployment '%s': %s", deploymentID, err)
+	}
+
+	if waitForHealthy {
+		// WaitForState doesn't return the last result on timeout, so the
+		// last deployment read is kept to report how far the canaries got.
+		refresh := canariesHealthyStateRefreshFunc(client, namespace, deploymentID)
+		stateConf = &resource.StateChangeConf{
+			Pending: []string{MonitoringCanaries},
+			Target:  []string{CanariesHealthy},
+			Refresh: func() (interface{}, string, error) {
+				result, state, err := refresh()
+				if d, ok := result.(*api.Deployment); ok {
+					deployment = d
+				}
+				return result, state, err
+			},
+			Timeout:    timeout,
+			Delay:      0,
+			MinTimeout: 5 * time.Second,
+		}
+
+		state, err = stateConf.WaitForState()
+		if err != nil {
+			if _, ok := err.(*resource.TimeoutError); ok {
+				return fmt.Errorf("canaries of deployment '%s' didn't become healthy: %s (%s)",
+					deploymentID, err, deploymentCanariesBreakdown(deployment))
+			}
+			return err
+		}
+		deployment = state.(*api.Deployment)
+	}
+
+	if !deploymentNeedsPromotion(deployment) {
+		log.Printf("[DEBUG] deployment '%s' has no canaries to promote", deploymentID)
+		return nil
+	}
+
+	log.Printf("[DEBUG] promoting canaries of deployment '%s'", deploymentID)
+	_, _, err = client.Deployments().PromoteAll(deploymentID, &api.WriteOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		// Nomad rejects the promotion of unhealthy canaries, which is
+		// expected when not waiting for them.
+		if !waitForHealthy && !deploymentCanariesHealthy(deployment) {
+			return fmt.Errorf("error promoting deployment '%s', its canaries aren't healthy yet (%s), set wait_for_canaries to wait for them: %s",
+				deploymentID, deploymentCanariesBreakdown(deployment), err)
+		}
+		return fmt.Errorf("error promoting deployment '%s': %s", deploymentID, err)
+	}
+	return nil
+}
+
+// canariesHealthyStateRefreshFunc returns a resource.StateRefreshFunc that is
+// used to watch the canaries of a deployment until they are all healthy.
+func canariesHealthyStateRefreshFunc(client *api.Client, namespace string, deploymentID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		deployment, _, err := client.Deployments().Info(deploymentID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			log.Printf("[ERROR] error on Deployment.Info during canariesHealthyStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		switch deployment.Status {
+		case api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+			return deployment, "",
+				fmt.Errorf("deployment '%s' terminated with status '%s' before promotion: '%s'",
+					deployment.ID, deployment.Status, deployment.StatusDescription)
+		case api.DeploymentStatusSuccessful:
+			return deployment, CanariesHealthy, nil
+		}
+
+		if deploymentCanariesHealthy(deployment) {
+			return deployment, CanariesHealthy, nil
+		}
+		log.Printf("[DEBUG] deployment '%s' in namespace '%s' canaries: %s",
+			deployment.ID, namespace, deploymentCanariesBreakdown(deployment))
+		return deployment, MonitoringCanaries, nil
+	}
+}
+
+// deploymentNeedsPromotion returns whether the deployment has task groups
+// with canaries that are not promoted yet.
+func deploymentNeedsPromotion(deployment *api.Deployment) bool {
+	if deployment.Status != api.DeploymentStatusRunning {
+		return false
+	}
+	for _, state := range deployment.TaskGroups {
+		if state != nil && state.DesiredCanaries > 0 && !state.Promoted {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentCanariesHealthy returns whether every task group of the
+// deployment waiting for promotion has all its canaries healthy.
+func deploymentCanariesHealthy(deployment *api.Deployment) bool {
+	for _, state := range deployment.TaskGroups {
+		if state == nil || state.DesiredCanaries == 0 || state.Promoted {
+			continue
+		}
+		if jobHealthyCanaries(state) < state.DesiredCanaries {
+			return false
+		}
+	}
+	return true
+}
+
+// deploymentCanariesBreakdown returns a per-group summary of the healthy
+// canaries of the deployment.
+func deploymentCanariesBreakdown(deployment *api.Deployment) string {
+	groups := make([]string, 0, len(deployment.TaskGroups))
+	for name, state := range deployment.TaskGroups {
+		if state != nil && state.DesiredCanaries > 0 {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+
+	parts := make([]string, 0, len(groups))
+	for _, name := range groups {
+		state := deployment.TaskGroups[name]
+		parts = append(parts, fmt.Sprintf("%s: %d/%d canaries healthy",
+			name, jobHealthyCanaries(state), state.DesiredCanaries))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// monitorDeployment monitors the evalution(s) from a job create/update and,
+// if they result in a deployment, monitors that deployment until completion
+// and until each group in requiredHealthy has enough healthy allocations.
+func monitorDeployment(client *api.Client, timeout time.Duration, namespace string, initialEvalID string, requiredHealthy map[string]int, failOnAutoRevert bool) (*api.Deployment, error) {
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{MonitoringEvaluation},
+		Target:     []string{EvaluationComplete},
+		Refresh:    evaluationStateRefreshFunc(client, namespace, initialEvalID),
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	state, err := stateConf.WaitForState()
+	if err != nil {
+		return nil, fmt.Errorf("error waiting for evaluation: %s", err)
+	}
+
+	evaluation := state.(*api.Evaluation)
+	if evaluation.DeploymentID == "" {
+		if len(requiredHealthy) > 0 {
+			return nil, fmt.Errorf("require_healthy is set, but there is no deployment to monitor")
+		}
+		log.Printf("[WARN] job has been scheduled, but there is no deployment to monitor")
+		return nil, nil
+	}
+
+	stateConf = &resource.StateChangeConf{
+		Pending:    []string{MonitoringDeployment},
+		Target:     []string{DeploymentSuccessful},
+		Refresh:    deploymentStateRefreshFunc(client, namespace, evaluation.DeploymentID, requiredHealthy, failOnAutoRevert),
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 5 * time.Second,
+	}
+
+	state, err = stateConf.WaitForState()
+	if err != nil {
+		if _, ok := err.(*resource.TimeoutError); ok && len(requiredHealthy) > 0 {
+			deployment, _, infoErr := client.Deployments().Info(evaluation.DeploymentID, &api.QueryOptions{
+				Namespace: namespace,
+			})
+			if infoErr == nil {
+				breakdown, _ := deploymentHealthyBreakdown(deployment, requiredHealthy)
+				return nil, fmt.Errorf("error waiting for deployment: %s (%s)", err, breakdown)
+			}
+		}
+		return nil, fmt.Errorf("error waiting for evaluation: %s", err)
+	}
+	return state.(*api.Deployment), nil
+}
+
+// monitorMultiregionDeployment waits for the deployment of the current version
+// of the job to be healthy in each region and returns the status of the
+// deployment in each region. A deployment is healthy once it's successful or
+// blocked waiting for the other regions to complete. When ordered, the regions
+// are driven one at a time: the blocked deployment of each region is unblocked
+// once healthy, before monitoring the next region.
+func monitorMultiregionDeployment(client *api.Client, timeout time.Duration, namespace string, jobID string, regions []string, ordered bool, failFast bool) (map[string]string, error) {
+	statuses := make(map[string]string, len(regions))
+
+	// Monitor and unblock the regions one at a time when ordered, or
+	// monitor all of them at once otherwise and let Nomad unblock them.
+	batches := [][]string{regions}
+	if ordered {
+		batches = make([][]string, 0, len(regions))
+		for _, region := range regions {
+			batches = append(batches, []string{region})
+		}
+	}
+
+	for _, batch := range batches {
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{MonitoringDeployment},
+			Target:     []string{DeploymentSuccessful},
+			Refresh:    multiregionDeploymentStateRefreshFunc(client, namespace, jobID, batch, statuses, ordered, failFast),
+			Timeout:    timeout,
+			Delay:      0,
+			MinTimeout: 5 * time.Second,
+		}
+
+		if _, err := stateConf.WaitForState(); err != nil {
+			if failFast {
+				failMultiregionDeployments(client, namespace, jobID, regions, statuses)
+			}
+			return statuses, err
+		}
+	}
+
+	var failed []string
+	for _, region := range regions {
+		if statuses[region] == api.DeploymentStatusFailed || statuses[region] == api.DeploymentStatusCancelled {
+			failed = append(failed, region)
+		}
+	}
+	if len(failed) > 0 {
+		return statuses, fmt.Errorf("deployment unsuccessful in regions: %s", strings.Join(failed, ", "))
+	}
+	return statuses, nil
+}
+
+// multiregionDeploymentStateRefreshFunc returns a resource.StateRefreshFunc
+// that is used to watch the deployments of the job in the given regions,
+// recording the status of each of them in statuses. When unblock is set, the
+// deployments that are healthy and blocked waiting for the other regions are
+// unblocked, so they complete and Nomad moves on to the next regions.
+func multiregionDeploymentStateRefreshFunc(client *api.Client, namespace string, jobID string, regions []string, statuses map[string]string, unblock bool, failFast bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		state := DeploymentSuccessful
+		for _, region := range regions {
+			deployment, err := multiregionCurrentDeployment(client, namespace, jobID, region)
+			if err != nil {
+				log.Printf("[ERROR] error reading deployment in region '%s': %s", region, err)
+				return nil, "", err
+			}
+			if deployment == nil {
+				// The deployment for the current version hasn't been
+				// created yet.
+				statuses[region] = api.DeploymentStatusPending
+				state = MonitoringDeployment
+				continue
+			}
+
+			statuses[region] = deployment.Status
+			switch deployment.Status {
+			case api.DeploymentStatusBlocked:
+				log.Printf("[DEBUG] deployment '%s' in region '%s' is healthy", deployment.ID, region)
+				if !unblock {
+					continue
+				}
+
+				log.Printf("[DEBUG] unblocking deployment '%s' in region '%s'", deployment.ID, region)
+				_, _, err := client.Deployments().Unblock(deployment.ID, &api.WriteOptions{
+					Namespace: namespace,
+					Region:    region,
+				})
+				if err != nil {
+					return nil, "", fmt.Errorf("error unblocking deployment '%s' in region '%s': %s", deployment.ID, region, err)
+				}
+				statuses[region] = api.DeploymentStatusSuccessful
+			case api.DeploymentStatusSuccessful:
+				log.Printf("[DEBUG] deployment '%s' in region '%s' is healthy", deployment.ID, region)
+			case api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+				log.Printf("[DEBUG] deployment unsuccessful in region '%s': %s", region, deployment.StatusDescription)
+				if failFast {
+					return nil, "",
+						fmt.Errorf("deployment '%s' in region '%s' terminated with status '%s': '%s'",
+							deployment.ID, region, deployment.Status, deployment.StatusDescription)
+				}
+			default:
+				state = MonitoringDeployment
+			}
+		}
+		return statuses, state, nil
+	}
+}
+
+// multiregionCurrentDeployment returns the deployment of the current version
+// of the job in the region, or nil if it hasn't been created yet.
+func multiregionCurrentDeployment(client *api.Client, namespace string, jobID string, region string) (*api.Deployment, error) {
+	q := &api.QueryOptions{
+		Namespace: namespace,
+		Region:    region,
+	}
+
+	job, _, err := client.Jobs().Info(jobID, q)
+	if err != nil {
+		return nil, err
+	}
+	deployment, _, err := client.Jobs().LatestDeployment(jobID, q)
+	if err != nil {
+		return nil, err
+	}
+	if deployment == nil || job.Version == nil || deployment.JobVersion != *job.Version {
+		return nil, nil
+	}
+	return deployment, nil
+}
+
+// failMultiregionDeployments fails the deployments of the job that are still
+// in progress in the given regions.
+func failMultiregionDeployments(client *api.Client, namespace string, jobID string, regions []string, statuses map[string]string) {
+	for _, region := range regions {
+		deployment, err := multiregionCurrentDeployment(client, namespace, jobID, region)
+		if err != nil || deployment == nil {
+			continue
+		}
+		switch deployment.Status {
+		case api.DeploymentStatusSuccessful, api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+			continue
+		}
+
+		log.Printf("[DEBUG] failing deployment '%s' in region '%s'", deployment.ID, region)
+		_, _, err = client.Deployments().Fail(deployment.ID, &api.WriteOptions{
+			Namespace: namespace,
+			Region:    region,
+		})
+		if err != nil {
+			log.Printf("[WARN] failed to fail deployment '%s' in region '%s': %s", deployment.ID, region, err)
+			continue
+		}
+		statuses[region] = api.DeploymentStatusFailed
+	}
+}
+
+// nomadServiceNames returns the names of the group and task services of the
+// job that use the Nomad service provider. Names that are interpolated at
+// runtime are skipped.
+func nomadServiceNames(job *api.Job) []string {
+	names := make(map[string]struct{})
+	addServices := func(services []*api.Service) {
+		for _, service := range services {
+			if service.Provider != "nomad" || service.Name == "" || strings.Contains(service.Name, "${") {
+				continue
+			}
+			names[service.Name] = struct{}{}
+		}
+	}
+
+	for _, tg := range job.TaskGroups {
+		addServices(tg.Services)
+		for _, task := range tg.Tasks {
+			addServices(task.Services)
+		}
+	}
+
+	ret := maps.Keys(names)
+	sort.Strings(ret)
+	return ret
+}
+
+// checksHealthGroups returns the task groups of the job whose deployment
+// health is based on service checks. Groups that set the health_check of
+// their update block to "task_states" or "manual" are left out.
+func checksHealthGroups(job *api.Job) map[string]bool {
+	jobMode := "checks"
+	if job.Update != nil && job.Update.HealthCheck != nil {
+		jobMode = *job.Update.HealthCheck
+	}
+
+	groups := make(map[string]bool, len(job.TaskGroups))
+	for _, tg := range job.TaskGroups {
+		if tg.Name == nil {
+			continue
+		}
+		mode := jobMode
+		if tg.Update != nil && tg.Update.HealthCheck != nil {
+			mode = *tg.Update.HealthCheck
+		}
+		if mode == "checks" {
+			groups[*tg.Name] = true
+		}
+	}
+	return groups
+}
+
+// ignoredHealthChecks returns the checks of the job whose on_update, or the
+// on_update of their service, is "ignore", keyed by healthCheckKey. Their
+// status doesn't affect the health of deployments.
+func ignoredHealthChecks(job *api.Job) map[string]bool {
+	ignored := make(map[string]bool)
+	addServices := func(group string, services []*api.Service) {
+		for _, service := range services {
+			for _, check := range service.Checks {
+				onUpdate := check.OnUpdate
+				if onUpdate == "" {
+					onUpdate = service.OnUpdate
+				}
+				if onUpdate != api.OnUpdateIgnore {
+					continue
+				}
+
+				// Nomad names unnamed checks after their service.
+				name := check.Name
+				if name == "" {
+					name = fmt.Sprintf("service: %q check", service.Name)
+				}
+				ignored[healthCheckKey(group, service.Name, name)] = true
+			}
+		}
+	}
+
+	for _, tg := range job.TaskGroups {
+		if tg.Name == nil {
+			continue
+		}
+		addServices(*tg.Name, tg.Services)
+		for _, task := range tg.Tasks {
+			addServices(*tg.Name, task.Services)
+		}
+	}
+	return ignored
+}
+
+func healthCheckKey(group, service, check string) string {
+	return group + "/" + service + "/" + check
+}
+
+// checkNomadServices verifies that the Nomad services are registered by the
+// allocations of the deployment and that none of their checks are failing.
+// Checks are only verified for the allocations of checkGroups, since the
+// health of other groups doesn't depend on them, and ignoredChecks are
+// skipped.
+func checkNomadServices(client *api.Client, namespace string, deploymentID string, services []string, checkGroups map[string]bool, ignoredChecks map[string]bool) error {
+	allocs, _, err := client.Deployments().Allocations(deploymentID, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading allocations of deployment '%s': %s", deploymentID, err)
+	}
+
+	allocIDs := make(map[string]struct{}, len(allocs))
+	checkAllocIDs := make([]string, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == api.AllocClientStatusRunning {
+			allocIDs[alloc.ID] = struct{}{}
+			if checkGroups[alloc.TaskGroup] {
+				checkAllocIDs = append(checkAllocIDs, alloc.ID)
+			}
+		}
+	}
+
+	for _, service := range services {
+		registrations, _, err := client.Services().Get(service, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			return fmt.Errorf("error reading registrations of service '%s': %s", service, err)
+		}
+
+		registered := false
+		for _, reg := range registrations {
+			if _, ok := allocIDs[reg.AllocID]; ok {
+				registered = true
+				break
+			}
+		}
+		if !registered {
+			return fmt.Errorf("service '%s' is not registered by any allocation of deployment '%s'", service, deploymentID)
+		}
+	}
+
+	for _, allocID := range checkAllocIDs {
+		checks, err := client.Allocations().Checks(allocID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			// Check results are served by the client running the
+			// allocation, which may not be reachable.
+			log.Printf("[WARN] failed to read checks of allocation '%s': %s", allocID, err)
+			continue
+		}
+		for _, check := range checks {
+			if ignoredChecks[healthCheckKey(check.Group, check.Service, check.Check)] {
+				continue
+			}
+			if check.Mode == "healthiness" && check.Status == "failure" {
+				return fmt.Errorf("check '%s' of service '%s' is failing in allocation '%s': %s",
+					check.Check, check.Service, allocID, check.Output)
+			}
+		}
+	}
+
+	return nil
+}
+
+// monitorAllocationChecks waits for the Nomad service checks of the running
+// allocations of the deployment to pass. Checks that are still failing or
+// pending after gracePeriod fail the apply, as do allocations whose checks
+// can't be read. ignoredChecks are skipped.
+func monitorAllocationChecks(client *api.Client, gracePeriod time.Duration, namespace string, deploymentID string, ignoredChecks map[string]bool) error {
+	allocs, _, err := client.Deployments().Allocations(deploymentID, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading allocations of deployment '%s': %s", deploymentID, err)
+	}
+
+	allocIDs := make([]string, 0, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == api.AllocClientStatusRunning {
+			allocIDs = append(allocIDs, alloc.ID)
+		}
+	}
+	sort.Strings(allocIDs)
+
+	var failing []string
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{MonitoringChecks},
+		Target:  []string{ChecksPassing},
+		Refresh: func() (interface{}, string, error) {
+			failing = failingAllocationChecks(client, namespace, allocIDs, ignoredChecks)
+			if len(failing) > 0 {
+				log.Printf("[DEBUG] %d checks of deployment '%s' are not passing", len(failing), deploymentID)
+				return allocIDs, MonitoringChecks, nil
+			}
+			return allocIDs, ChecksPassing, nil
+		},
+		Timeout:    gracePeriod,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err = stateConf.WaitForState()
+	if _, ok := err.(*resource.TimeoutError); ok {
+		return fmt.Errorf("checks not passing after %s: %s", gracePeriod, strings.Join(failing, "; "))
+	}
+	return err
+}
+
+// failingAllocationChecks returns a description of the healthiness checks of
+// the allocations that aren't passing.
+func failingAllocationChecks(client *api.Client, namespace string, allocIDs []string, ignoredChecks map[string]bool) []string {
+	var failing []string
+	for _, allocID := range allocIDs {
+		checks, err := client.Allocations().Checks(allocID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			failing = append(failing, fmt.Sprintf("failed to read checks of allocation '%s': %s", allocID, err))
+			continue
+		}
+
+		ids := maps.Keys(checks)
+		sort.Strings(ids)
+		for _, id := range ids {
+			check := checks[id]
+			if ignoredChecks[healthCheckKey(check.Group, check.Service, check.Check)] {
+				continue
+			}
+			if check.Mode == "healthiness" && check.Status != "success" {
+				failing = append(failing, fmt.Sprintf("check '%s' of service '%s' is %s in allocation '%s': %s",
+					check.Check, check.Service, check.Status, allocID, check.Output))
+			}
+		}
+	}
+	return failing
+}
+
+// deploymentHealthyBreakdown returns a per-group summary of the healthy
+// allocations of the deployment against the required counts and whether all
+// of them have been reached.
+func deploymentHealthyBreakdown(deployment *api.Deployment, requiredHealthy map[string]int) (string, bool) {
+	groups := maps.Keys(requiredHealthy)
+	sort.Strings(groups)
+
+	met := true
+	breakdown := make([]string, 0, len(groups))
+	for _, group := range groups {
+		healthy := 0
+		if state, ok := deployment.TaskGroups[group]; ok && state != nil {
+			healthy = state.HealthyAllocs
+		}
+		if healthy < requiredHealthy[group] {
+			met = false
+		}
+		breakdown = append(breakdown, fmt.Sprintf("%s: %d/%d healthy", group, healthy, requiredHealthy[group]))
+	}
+
+	return strings.Join(breakdown, ", "), met
+}
+
+// evaluationStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
+// the evaluation(s) from a job create/update
+func evaluationStateRefreshFunc(client *api.Client, namespace string, initialEvalID string) resource.StateRefreshFunc {
+
+	// evalID is the evaluation that we are currently monitoring. This will change
+	// along with follow-up evaluations.
+	evalID := initialEvalID
+
+	return func() (interface{}, string, error) {
+		// monitor the eval
+		log.Printf("[DEBUG] monitoring evaluation '%s' in namespace '%s'", evalID, namespace)
+		eval, _, err := client.Evaluations().Info(evalID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			log.Printf("[ERROR] error on Evaluation.Info during deploymentStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		var state string
+		switch eval.Status {
+		case "complete":
+			// Monitor the next eval in the chain, if present
+			log.Printf("[DEBUG] evaluation '%v' in namespace '%s' complete", eval.ID, namespace)
+			if eval.NextEval != "" {
+				log.Printf("[DEBUG] will monitor follow-up eval '%v'", eval.ID)
+				evalID = eval.NextEval
+				state = MonitoringEvaluation
+			} else {
+				state = EvaluationComplete
+			}
+		case "failed", "cancelled":
+			return nil, "", fmt.Errorf("evaluation failed: %v", eval.StatusDescription)
+		default:
+			state = MonitoringEvaluation
+		}
+		return eval, state, nil
+	}
+}
+
+// deploymentStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
+// the deployment from a job create/update
+func deploymentStateRefreshFunc(client *api.Client, namespace string, deploymentID string, requiredHealthy map[string]int, failOnAutoRevert bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		// monitor the deployment
+		var state string
+		deployment, _, err := client.Deployments().Info(deploymentID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			log.Printf("[ERROR] error on Deployment.Info during deploymentStateRefresh: %s", err)
+			return nil, "", err
+		}
+		breakdown, healthy := deploymentHealthyBreakdown(deployment, requiredHealthy)
+		switch deployment.Status {
+		case "successful":
+			if !healthy {
+				return deployment, "",
+					fmt.Errorf("deployment '%s' completed without reaching the required healthy allocations: %s",
+						deployment.ID, breakdown)
+			}
+			log.Printf("[DEBUG] deployment '%s' in namespace '%s' successful", deployment.ID, namespace)
+			state = DeploymentSuccessful
+		case "failed", "cancelled":
+			log.Printf("[DEBUG] deployment unsuccessful: %s", deployment.StatusDescription)
+			details := deploymentFailureDetails(client, namespace, deployment)
+			if version, ok := deploymentAutoRevertVersion(deployment); ok && failOnAutoRevert {
+				return deployment, "",
+					fmt.Errorf("deployment '%s' auto-reverted to version %d: '%s'%s",
+						deployment.ID, version, deployment.StatusDescription, details)
+			}
+			return deployment, "",
+				fmt.Errorf("deployment '%s' terminated with status '%s': '%s'%s",
+					deployment.ID, deployment.Status, deployment.StatusDescription, details)
+		default:
+			if len(requiredHealthy) > 0 {
+				log.Printf("[DEBUG] deployment '%s' in namespace '%s' healthy allocations: %s", deployment.ID, namespace, breakdown)
+			}
+			// don't overwhelm the API server
+			state = MonitoringDeployment
+		}
+		return deployment, state, nil
+	}
+}
+
+// deploymentAutoRevertRe matches the status description Nomad sets on a failed
+// deployment when the job is reverted to its last stable version.
+var deploymentAutoRevertRe = regexp.MustCompile(`rolling back to job version (\d+)`)
+
+// deploymentAutoRevertVersion returns the version of the job the deployment
+// was auto-reverted to, if it was.
+func deploymentAutoRevertVersion(deployment *api.Deployment) (uint64, bool) {
+	match := deploymentAutoRevertRe.FindStringSubmatch(deployment.StatusDescription)
+	if match == nil {
+		return 0, false
+	}
+
+	version, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// deploymentFailureHints maps the status descriptions Nomad sets on
+// unsuccessful deployments to remediation hints. Descriptions are matched by
+// prefix since Nomad may append details, such as the version the job was
+// rolled back to.
+var deploymentFailureHints = []struct {
+	description string
+	hint        string
+}{
+	{
+		description: "Failed due to unhealthy allocations",
+		hint: "allocations failed their health checks or were not healthy for min_healthy_time; " +
+			"check the task events and logs of the failing allocations and the job's service checks, " +
+			"or increase healthy_deadline in the update block",
+	},
+	{
+		description: "Failed due to progress deadline",
+		hint: "allocations did not become healthy before the progress_deadline of the update block; " +
+			"check that the cluster has enough capacity to place the allocations " +
+			"and increase progress_deadline if the tasks are slow to start",
+	},
+	{
+		description: "Deadline exceeded",
+		hint: "the deployment did not complete before its deadline; " +
+			"increase progress_deadline or healthy_deadline in the update block",
+	},
+	{
+		description: "Deployment marked as failed",
+		hint:        "the deployment was failed manually, for example with `nomad deployment fail`",
+	},
+	{
+		description: "Cancelled because job is stopped",
+		hint:        "the job was stopped while the deployment was running",
+	},
+	{
+		description: "Cancelled due to newer version of job",
+		hint:        "the job was updated by another client while the deployment was running",
+	},
+}
+
+// deploymentFailureHint returns the remediation hint for the status
+// description of an unsuccessful deployment, if there is one.
+func deploymentFailureHint(description string) string {
+	for _, h := range deploymentFailureHints {
+		if strings.HasPrefix(description, h.description) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+// deploymentFailureDetails returns the remediation hint and the last task
+// event of the failing allocations of an unsuccessful deployment, to be
+// appended to the error returned for it.
+func deploymentFailureDetails(client *api.Client, namespace string, deployment *api.Deployment) string {
+	var details string
+	if hint := deploymentFailureHint(deployment.StatusDescription); hint != "" {
+		details += fmt.Sprintf("\n\nHint: %s", hint)
+	}
+
+	allocs, _, err := client.Deployments().Allocations(deployment.ID, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		log.Printf("[WARN] error listing allocations of deployment '%s': %s", deployment.ID, err)
+		return details
+	}
+	if allocID, task, event := failedAllocLastTaskEvent(allocs); event != nil {
+		message := event.DisplayMessage
+		if message == "" {
+			message = event.Message
+		}
+		details += fmt.Sprintf("\n\nLast event of task '%s' in allocation '%s': %s: %s",
+			task, allocID, event.Type, message)
+	}
+	return details
+}
+
+// failedAllocLastTaskEvent returns the most recent task event of the
+// allocations that are unhealthy or failed, along with the allocation ID and
+// the task name.
+func failedAllocLastTaskEvent(allocs []*api.AllocationListStub) (string, string, *api.TaskEvent) {
+	var allocID, task string
+	var last *api.TaskEvent
+	for _, alloc := range allocs {
+		unhealthy := alloc.DeploymentStatus != nil &&
+			alloc.DeploymentStatus.Healthy != nil && !*alloc.DeploymentStatus.Healthy
+		if !unhealthy && alloc.ClientStatus != api.AllocClientStatusFailed {
+			continue
+		}
+
+		for name, state := range alloc.TaskStates {
+			if state == nil {
+				continue
+			}
+			for _, event := range state.Events {
+				if event != nil && (last == nil || event.Time > last.Time) {
+					allocID, task, last = alloc.ID, name, event
+				}
+			}
+		}
+	}
+	return allocID, task, last
+}
+
+// resourceJobDestroy warns about the number of nodes targeted by system jobs
+// and the regions left running by multiregion jobs, and deregisters the job.
+func resourceJobDestroy(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.Get("deregister_on_destroy").(bool) {
+		diags = systemJobTargetNodesDiags(d, meta)
+		diags = append(diags, multiregionDeregisterDiags(d, meta)...)
+	}
+
+	if err := resourceJobDeregister(d, meta); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	return diags
+}
+
+func resourceJobDeregister(d *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	// If deregistration is disabled, then do nothing
+	deregister_on_destroy := d.Get("deregister_on_destroy").(bool)
+	if !deregister_on_destroy {
+		log.Printf(
+			"[WARN] job %q will not deregister since "+
+				"'deregister_on_destroy' is %t", d.Id(), deregister_on_destroy)
+		return nil
+	}
+
+	id := d.Id()
+	log.Printf("[DEBUG] deregistering job: %q", id)
+	opts := &api.WriteOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+
+	// A partially managed job is only deregistered once no other task group
+	// remains, otherwise its managed groups are removed from the job.
+	if managed := resourceJobManagedGroups(d); len(managed) > 0 {
+		removed, err := removeManagedTaskGroups(client, opts.Namespace, id, managed, d.Get("policy_override").(bool))
+		if err != nil {
+			return err
+		}
+		if removed {
+			return nil
+		}
+	}
+
+	purge := d.Get("purge_on_destroy").(bool)
+	forceDestroy := d.Get("force_destroy").(bool)
+	if forceDestroy {
+		log.Printf("[DEBUG] force destroying job %q, it will be purged", id)
+		purge = true
+		if err := failActiveDeployment(client, opts.Namespace, id); err != nil {
+			log.Printf("[WARN] failed to fail active deployment of job %q: %s", id, err)
+		}
+	}
+
+	evalID, _, err := client.Jobs().DeregisterOpts(id, &api.DeregisterOptions{
+		Purge:           purge,
+		Global:          d.Get("global_deregister").(bool),
+		EvalPriority:    d.Get("eval_priority").(int),
+		NoShutdownDelay: forceDestroy,
+	}, opts)
+	if err != nil {
+		if !forceDestroy || !strings.Contains(err.Error(), "404") {
+			return fmt.Errorf("error deregistering job: %s", err)
+		}
+		log.Printf("[DEBUG] job %q not found, assuming it was already purged", id)
+	} else {
+		log.Printf("[INFO] deregistered job %q with evaluation %q", id, evalID)
+		if forceDestroy {
+			log.Printf("[DEBUG] purged job %q", id)
+		}
+	}
+
+	if d.Get("wait_for_deregister").(bool) && evalID != "" {
+		log.Printf("[DEBUG] waiting for deregister evaluation %q of job %q", evalID, id)
+		stateConf := &resource.StateChangeConf{
+			Pending:    []string{MonitoringEvaluation},
+			Target:     []string{EvaluationComplete},
+			Refresh:    evaluationStateRefreshFunc(client, opts.Namespace, evalID),
+			Timeout:    d.Timeout(schema.TimeoutDelete),
+			Delay:      0,
+			MinTimeout: 3 * time.Second,
+		}
+		if _, err := stateConf.WaitForState(); err != nil {
+			return fmt.Errorf("error waiting for deregister evaluation %q of job %q: %s", evalID, id, err)
+		}
+	}
+
+	if d.Get("purge_children_on_destroy").(bool) {
+		if err := deregisterChildJobs(client, id, purge, opts); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("wait_for_destroy").(bool) {
+		timeout := d.Timeout(schema.TimeoutDelete)
+		if stopTimeout := d.Get("stop_timeout").(string); stopTimeout != "" {
+			timeout, err = time.ParseDuration(stopTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid stop_timeout: %s", err)
+			}
+		}
+
+		log.Printf("[DEBUG] waiting up to %s for job %q to be destroyed", timeout, id)
+		err := monitorJobDestroy(client, timeout, opts.Namespace, id, purge)
+		if err != nil {
+			return fmt.Errorf("error waiting for job %q to be destroyed: %s", id, err)
+		}
+	}
+
+	return nil
+}
+
+// deregisterMovedJob deregisters the job from the namespace it was moved from.
+func deregisterMovedJob(d *schema.ResourceData, meta interface{}, jobID string, namespace string) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] deregistering job %q from previous namespace %q", jobID, namespace)
+	_, _, err := client.Jobs().DeregisterOpts(jobID, &api.DeregisterOptions{
+		Purge: d.Get("purge_on_destroy").(bool),
+	}, &api.WriteOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			log.Printf("[DEBUG] job %q not found in namespace %q, assuming it was already deregistered", jobID, namespace)
+			return nil
+		}
+		return fmt.Errorf("job %q was registered in namespace %q, but failed to be deregistered from namespace %q: %s",
+			d.Id(), d.Get("namespace").(string), namespace, err)
+	}
+	return nil
+}
+
+// resourceJobRestartOnVariableChange restarts the allocations of the job if
+// any of the variables in restart_on changed since the last apply.
+func resourceJobRestartOnVariableChange(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	oldIndexes, newIndexes := d.GetChange("variable_modify_indexes")
+	changed := changedVariablePaths(oldIndexes.(map[string]interface{}), newIndexes.(map[string]interface{}))
+	if len(changed) > 0 {
+		log.Printf("[DEBUG] variables %s changed, restarting allocations of job %q",
+			strings.Join(changed, ", "), d.Id())
+		if err := restartJobAllocations(client, d.Get("namespace").(string), d.Id()); err != nil {
+			return fmt.Errorf("error restarting allocations of job %q: %s", d.Id(), err)
+		}
+	}
+
+	if err := resourceJobSetVariableModifyIndexes(d, meta); err != nil {
+		return err
+	}
+	return resourceJobRead(d, meta)
+}
+
+// resourceJobSetVariableModifyIndexes stores the modify indexes of the
+// variables in restart_on. The indexes planned by CustomizeDiff are kept, so
+// changes made after the plan are detected by the next one.
+func resourceJobSetVariableModifyIndexes(d *schema.ResourceData, meta interface{}) error {
+	paths := restartOnVariablePaths(d)
+	if len(paths) == 0 {
+		return d.Set("variable_modify_indexes", nil)
+	}
+	if len(d.Get("variable_modify_indexes").(map[string]interface{})) > 0 {
+		return nil
+	}
+
+	client := meta.(ProviderConfig).client
+	indexes, err := variableModifyIndexes(client, d.Get("namespace").(string), paths)
+	if err != nil {
+		return fmt.Errorf("error reading variables of restart_on: %s", err)
+	}
+	return d.Set("variable_modify_indexes", indexes)
+}
+
+// restartOnVariablePaths returns the variable paths of the restart_on block.
+func restartOnVariablePaths(d ResourceFieldGetter) []string {
+	restartOn, ok := d.Get("restart_on").([]interface{})
+	if !ok || len(restartOn) == 0 || restartOn[0] == nil {
+		return nil
+	}
+
+	var paths []string
+	for _, path := range restartOn[0].(map[string]interface{})["variable_paths"].([]interface{}) {
+		if path, ok := path.(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// variableModifyIndexes returns the modify index of each variable, or "0" if
+// the variable doesn't exist.
+func variableModifyIndexes(client *api.Client, namespace string, paths []string) (map[string]string, error) {
+	indexes := make(map[string]string, len(paths))
+	for _, path := range paths {
+		variable, _, err := client.Variables().Peek(path, &api.QueryOptions{Namespace: namespace})
+		if err != nil {
+			return nil, fmt.Errorf("error reading variable %q: %s", path, err)
+		}
+		index := uint64(0)
+		if variable != nil {
+			index = variable.ModifyIndex
+		}
+		indexes[path] = strconv.FormatUint(index, 10)
+	}
+	return indexes, nil
+}
+
+// changedVariablePaths returns the sorted paths whose modify index changed.
+// Variables that were not tracked before are not considered changed.
+func changedVariablePaths(oldIndexes, newIndexes map[string]interface{}) []string {
+	var changed []string
+	for path, index := range newIndexes {
+		if oldIndex, ok := oldIndexes[path]; ok && oldIndex != index {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// restartJobAllocations restarts the running tasks of the running allocations
+// of the job.
+func restartJobAllocations(client *api.Client, namespace string, jobID string) error {
+	opts := &api.QueryOptions{Namespace: namespace}
+	allocs, _, err := client.Jobs().Allocations(jobID, false, opts)
+	if err != nil {
+		return fmt.Errorf("error listing allocations: %s", err)
+	}
+
+	for _, alloc := range allocs {
+		if alloc.ClientStatus != api.AllocClientStatusRunning {
+			continue
+		}
+		log.Printf("[DEBUG] restarting allocation %q of job %q", alloc.ID, jobID)
+		if err := client.Allocations().Restart(&api.Allocation{ID: alloc.ID}, "", opts); err != nil {
+			return fmt.Errorf("error restarting allocation %q: %s", alloc.ID, err)
+		}
+	}
+	return nil
+}
+
+// failActiveDeployment fails the latest deployment of the job if it's still
+// in progress, so a stuck deployment doesn't block its deregistration.
+func failActiveDeployment(client *api.Client, namespace string, jobID string) error {
+	deployment, _, err := client.Jobs().LatestDeployment(jobID, &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return err
+	}
+	if deployment == nil {
+		log.Printf("[DEBUG] job %q has no deployment to fail", jobID)
+		return nil
+	}
+	switch deployment.Status {
+	case api.DeploymentStatusSuccessful, api.DeploymentStatusFailed, api.DeploymentStatusCancelled:
+		log.Printf("[DEBUG] latest deployment %q of job %q is %s, not failing it", deployment.ID, jobID, deployment.Status)
+		return nil
+	}
+
+	log.Printf("[DEBUG] failing deployment %q of job %q", deployment.ID, jobID)
+	_, _, err = client.Deployments().Fail(deployment.ID, &api.WriteOptions{
+		Namespace: namespace,
+	})
+	return err
+}
+
+// multiregionDeregisterDiags returns a warning if the job is multiregion and
+// global_deregister is not set, since only the job in the region of the
+// provider is deregistered.
+func multiregionDeregisterDiags(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("global_deregister").(bool) {
+		return nil
+	}
+
+	client := meta.(ProviderConfig).client
+	job, _, err := client.Jobs().Info(d.Id(), &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to read job %q to check its regions: %s", d.Id(), err)
+		return nil
+	}
+	if job.Multiregion == nil || len(job.Multiregion.Regions) == 0 {
+		return nil
+	}
+
+	regions := make([]string, 0, len(job.Multiregion.Regions))
+	for _, region := range job.Multiregion.Regions {
+		regions = append(regions, region.Name)
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Multiregion job %q is only deregistered from region %q", d.Id(), *job.Region),
+		Detail: fmt.Sprintf("The job is deployed to the regions %s. Set global_deregister to true "+
+			"to deregister it from all of them when the resource is destroyed.", strings.Join(regions, ", ")),
+	}}
+}
+
+// jobSentinelRejection describes the Sentinel policies that rejected a job
+// registration. It returns an empty string if err is not a Sentinel failure
+// or if the failing policies cannot be determined.
+func jobSentinelRejection(client *api.Client, namespace string, err error) string {
+	if !isSentinelFailure(err) {
+		return ""
+	}
+
+	policies, _, listErr := client.SentinelPolicies().List(&api.QueryOptions{
+		Namespace: namespace,
+	})
+	if listErr != nil {
+		log.Printf("[WARN] failed to list Sentinel policies: %s", listErr)
+		return ""
+	}
+
+	names := make([]string, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p.Name)
+	}
+	failing := sentinelFailingPolicies(err.Error(), names)
+	if len(failing) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(failing))
+	for _, name := range failing {
+		policy, _, infoErr := client.SentinelPolicies().Info(name, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if infoErr != nil {
+			log.Printf("[WARN] failed to read Sentinel policy %q: %s", name, infoErr)
+			lines = append(lines, fmt.Sprintf("  - %s", name))
+			continue
+		}
+
+		line := fmt.Sprintf("  - %s (%s)", policy.Name, policy.EnforcementLevel)
+		if policy.Description != "" {
+			line += ": " + policy.Description
+		}
+		lines = append(lines, line)
+	}
+
+	return fmt.Sprintf("job rejected by Sentinel policies:\n%s", strings.Join(lines, "\n"))
+}
+
+// isSentinelFailure returns true if err looks like a Sentinel policy
+// rejection returned by the job register endpoint.
+func isSentinelFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(strings.ToLower(msg), "sentinel") || strings.Contains(msg, "Result: false")
+}
+
+// sentinelFailingPolicies returns the names of the policies in names that
+// are reported as failing in the Sentinel error message msg.
+func sentinelFailingPolicies(msg string, names []string) []string {
+	var failing []string
+	for _, name := range names {
+		re := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(name) + `($|[^\w.-])`)
+		if re.MatchString(msg) {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// purgeDeadJob purges the job and waits until it is no longer found.
+func purgeDeadJob(client *api.Client, timeout time.Duration, namespace string, jobID string) error {
+	log.Printf("[DEBUG] purging dead job %q in namespace %q before running it again", jobID, namespace)
+	_, _, err := client.Jobs().Deregister(jobID, true, &api.WriteOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		return fmt.Errorf("error purging dead job %q: %s", jobID, err)
+	}
+
+	if err := monitorJobDestroy(client, timeout, namespace, jobID, true); err != nil {
+		return fmt.Errorf("error waiting for dead job %q to be purged: %s", jobID, err)
+	}
+	return nil
+}
+
+// monitorJobDestroy waits until the job is no longer found, if purge is
+// true, or until its status is dead. On timeout, the error includes the last
+// status of the job.
+func monitorJobDestroy(client *api.Client, timeout time.Duration, namespace string, jobID string, purge bool) error {
+	var status string
+	refresh := jobDestroyStateRefreshFunc(client, namespace, jobID, purge)
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{MonitoringDestroy},
+		Target:  []string{JobDestroyed},
+		Refresh: func() (interface{}, string, error) {
+			result, state, err := refresh()
+			if job, ok := result.(*api.Job); ok && job.Status != nil {
+				status = *job.Status
+			}
+			return result, state, err
+		},
+		Timeout:    timeout,
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	_, err := stateConf.WaitForState()
+	if _, ok := err.(*resource.TimeoutError); ok && status != "" {
+		if purge {
+			return fmt.Errorf("job wasn't purged after %s, its status is %q", timeout, status)
+		}
+		return fmt.Errorf("job didn't stop after %s, its status is %q", timeout, status)
+	}
+	return err
+}
+
+// jobDestroyStateRefreshFunc returns a resource.StateRefreshFunc that is used
+// to watch a job being deregistered or purged.
+func jobDestroyStateRefreshFunc(client *api.Client, namespace string, jobID string, purge bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		job, _, err := client.Jobs().Info(jobID, &api.QueryOptions{
+			Namespace: namespace,
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), "404") {
+				log.Printf("[DEBUG] job %q in namespace %q not found", jobID, namespace)
+				return jobID, JobDestroyed, nil
+			}
+			log.Printf("[ERROR] error on Job.Info during jobDestroyStateRefresh: %s", err)
+			return nil, "", err
+		}
+
+		if !purge && job.Status != nil && *job.Status == "dead" {
+			log.Printf("[DEBUG] job %q in namespace %q is dead", jobID, namespace)
+			return job, JobDestroyed, nil
+		}
+		return job, MonitoringDestroy, nil
+	}
+}
+
+// systemJobTargetNodesDiags returns a warning with the number of nodes
+// targeted by the job if it is a system job, so operators get an idea of the
+// impact of registering or destroying it.
+func systemJobTargetNodesDiags(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.Get("type").(string) != "system" {
+		return nil
+	}
+
+	client := meta.(ProviderConfig).client
+	namespace := d.Get("namespace").(string)
+
+	job, _, err := client.Jobs().Info(d.Id(), &api.QueryOptions{
+		Namespace: namespace,
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to read job %q to count its target nodes: %s", d.Id(), err)
+		return nil
+	}
+
+	nodes, _, err := client.Nodes().List(&api.QueryOptions{
+		Params: map[string]string{"os": "true"},
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to list nodes targeted by job %q: %s", d.Id(), err)
+		return nil
+	}
+
+	targeted := jobTargetNodes(job, nodes)
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("System job %q targets %d of %d nodes", d.Id(), targeted, len(nodes)),
+		Detail: "The number of nodes is derived from the ready and eligible nodes matching " +
+			"the job datacenters, node pool and constraints. Constraints the provider " +
+			"can't evaluate, such as those on node metadata, are assumed to match.",
+	}}
+}
+
+// jobNoEligibleNodesDiags returns a warning if the registered job doesn't
+// match any node.
+func jobNoEligibleNodesDiags(d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(ProviderConfig).client
+
+	job, _, err := client.Jobs().Info(d.Id(), &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	})
+	if err != nil {
+		log.Printf("[WARN] failed to read job %q to find eligible nodes: %s", d.Id(), err)
+		return nil
+	}
+
+	warning, err := jobNoEligibleNodesWarning(client, job)
+	if err != nil {
+		log.Printf("[WARN] failed to list nodes eligible for job %q: %s", d.Id(), err)
+		return nil
+	}
+	if warning == nil {
+		return nil
+	}
+	return diag.Diagnostics{*warning}
+}
+
+// jobVersionCountWarning returns a warning if the job has more versions than
+// maxVersions. A maxVersions of 0 disables the warning.
+func jobVersionCountWarning(jobID string, count int, maxVersions int) *diag.Diagnostic {
+	if maxVersions == 0 || count <= maxVersions {
+		return nil
+	}
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Job %q has %d versions", jobID, count),
+		Detail: fmt.Sprintf("The job has more versions than max_versions (%d), which may indicate "+
+			"that it is changed more often than expected.", maxVersions),
+	}
+}
+
+// deploymentDeadlineMargin is added to the effective deadline of a job when
+// it's used to monitor its deployment, so Nomad fails a deployment that
+// misses its deadlines before the provider stops waiting for it.
+const deploymentDeadlineMargin = time.Minute
+
+// jobEffectiveDeadline returns the longest of the min_healthy_time,
+// healthy_deadline and progress_deadline of the update strategies of the task
+// groups of a canonicalized job. It returns 0 for jobs other than service
+// jobs, since they don't have deployments.
+func jobEffectiveDeadline(job *api.Job) time.Duration {
+	if job.Type == nil || *job.Type != api.JobTypeService {
+		return 0
+	}
+
+	var deadline time.Duration
+	for _, tg := range job.TaskGroups {
+		if tg.Update == nil {
+			continue
+		}
+		for _, d := range []*time.Duration{tg.Update.MinHealthyTime, tg.Update.HealthyDeadline, tg.Update.ProgressDeadline} {
+			if d != nil {
+				deadline = max(deadline, *d)
+			}
+		}
+	}
+	return deadline
+}
+
+func effectiveDeadlineRaw(deadline time.Duration) string {
+	if deadline == 0 {
+		return ""
+	}
+	return deadline.String()
+}
+
+// resourceJobTimeoutConfigured returns whether the timeout of the given
+// operation is set in the timeouts block of the resource.
+func resourceJobTimeoutConfigured(d ResourceConfigGetter, key string) bool {
+	raw := d.GetRawConfig()
+	if !raw.IsKnown() || raw.IsNull() || !raw.Type().HasAttribute("timeouts") {
+		return false
+	}
+	timeouts := raw.GetAttr("timeouts")
+	if !timeouts.IsKnown() || timeouts.IsNull() || !timeouts.Type().HasAttribute(key) {
+		return false
+	}
+	return !timeouts.GetAttr(key).IsNull()
+}
+
+// jobPreviousStableVersion returns the most recent stable version before the
+// current version of the job, or -1 if there is none.
+func jobPreviousStableVersion(versions []*api.Job, current *uint64) int {
+	previous := -1
+	for _, v := range versions {
+		if v.Version == nil || v.Stable == nil || !*v.Stable {
+			continue
+		}
+		if current != nil && *v.Version >= *current {
+			continue
+		}
+		previous = max(previous, int(*v.Version))
+	}
+	return previous
+}
+
+// jobRegionWarning returns a warning if the jobspec sets a region other than
+// the region the provider sends requests to. Multiregion jobs set their
+// region per region, so they are not checked.
+func jobRegionWarning(providerConfig ProviderConfig, job *api.Job) *diag.Diagnostic {
+	if job.Region == nil || *job.Region == "" || job.Multiregion != nil {
+		return nil
+	}
+
+	region := providerConfig.config.Region
+	if region == "" {
+		var err error
+		region, err = providerConfig.client.Agent().Region()
+		if err != nil {
+			log.Printf("[WARN] failed to read the region of the Nomad agent: %s", err)
+			return nil
+		}
+	}
+
+	return jobRegionMismatchWarning(*job.ID, *job.Region, region)
+}
+
+// jobRegionMismatchWarning returns a warning if jobRegion is not region.
+func jobRegionMismatchWarning(jobID, jobRegion, region string) *diag.Diagnostic {
+	if jobRegion == region {
+		return nil
+	}
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Job %q is pinned to region %q, but the provider uses region %q", jobID, jobRegion, region),
+		Detail: "The job is registered in the region set in the jobspec, so it may not run where " +
+			"expected. Remove region from the jobspec or configure the region of the provider.",
+	}
+}
+
+// jobUpdateStrategyWarnings returns warnings for task groups whose update,
+// migrate and reschedule settings conflict, so the job doesn't roll out as
+// configured. job must not be canonicalized, since canonicalization adds a
+// default migrate block to every service task group.
+func jobUpdateStrategyWarnings(job *api.Job) diag.Diagnostics {
+	jobType := "service"
+	if job.Type != nil && *job.Type != "" {
+		jobType = *job.Type
+	}
+
+	var diags diag.Diagnostics
+	for _, tg := range job.TaskGroups {
+		name := *tg.Name
+		hasMigrate := job.Migrate != nil || tg.Migrate != nil
+
+		if hasMigrate && jobType != "service" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Task group %q of %s job %q has a migrate block", name, jobType, *job.ID),
+				Detail:   "The migrate block only applies to service jobs and is ignored. Remove it from the jobspec.",
+			})
+		}
+
+		update := job.Update.Copy()
+		if update == nil {
+			update = tg.Update
+		} else {
+			update.Merge(tg.Update)
+		}
+		if update == nil || update.MaxParallel == nil || *update.MaxParallel != 0 {
+			continue
+		}
+
+		if hasMigrate && jobType == "service" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Task group %q of job %q has a migrate block, but max_parallel is 0", name, *job.ID),
+				Detail: "Setting max_parallel to 0 in the update block disables deployments, so allocations " +
+					"are not replaced according to the health checks of the migrate block. Set max_parallel " +
+					"to a positive value or remove the migrate block.",
+			})
+		}
+		if update.Canary != nil && *update.Canary > 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Task group %q of job %q has canaries, but max_parallel is 0", name, *job.ID),
+				Detail: "Setting max_parallel to 0 in the update block disables deployments, so no canaries " +
+					"are placed. Set max_parallel to a positive value or remove canary.",
+			})
+		}
+		if update.AutoRevert != nil && *update.AutoRevert {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Task group %q of job %q has auto_revert set, but max_parallel is 0", name, *job.ID),
+				Detail: "Setting max_parallel to 0 in the update block disables deployments, so failed " +
+					"updates are never reverted. Set max_parallel to a positive value or remove auto_revert.",
+			})
+		}
+	}
+	return diags
+}
+
+// jobServicePortWarnings returns warnings for services whose port is a label
+// that isn't declared in the network blocks of their task group, since Nomad
+// only fails to register them once the allocations are placed. Services using
+// the driver address mode may refer to ports declared by the task driver, so
+// they are not checked.
+func jobServicePortWarnings(job *api.Job) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, tg := range job.TaskGroups {
+		labels := networkPortLabels(tg.Networks)
+
+		check := func(owner string, services []*api.Service, labels map[string]bool) {
+			for _, service := range services {
+				if !serviceMissingPortLabel(service, labels) {
+					continue
+				}
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary: fmt.Sprintf("Service %q of %s in job %q uses undeclared port %q",
+						service.Name, owner, *job.ID, service.PortLabel),
+					Detail: fmt.Sprintf("The port label %q is not declared in the network block of task group %q, "+
+						"so the service will fail to register. Declare the port or use a port number.",
+						service.PortLabel, *tg.Name),
+				})
+			}
+		}
+
+		check(fmt.Sprintf("task group %q", *tg.Name), tg.Services, labels)
+		for _, task := range tg.Tasks {
+			taskLabels := labels
+			if task.Resources != nil && len(task.Resources.Networks) > 0 {
+				taskLabels = networkPortLabels(task.Resources.Networks)
+				for label := range labels {
+					taskLabels[label] = true
+				}
+			}
+			check(fmt.Sprintf("task %q", task.Name), task.Services, taskLabels)
+		}
+	}
+	return diags
 }
 
-// JSONJobParserConfig stores configuration options for the JSON jobspec parser.
-type JSONJobParserConfig struct {
-	Enabled bool
+// networkPortLabels returns the labels of the ports declared in networks.
+func networkPortLabels(networks []*api.NetworkResource) map[string]bool {
+	labels := make(map[string]bool)
+	for _, network := range networks {
+		if network == nil {
+			continue
+		}
+		for _, port := range network.ReservedPorts {
+			labels[port.Label] = true
+		}
+		for _, port := range network.DynamicPorts {
+			labels[port.Label] = true
+		}
+	}
+	return labels
 }
 
-// HCL1JobParserConfig stores configuration options for the HCL1 jobspec parser.
-type HCL1JobParserConfig struct {
-	Enabled bool
+// serviceMissingPortLabel returns whether the port of the service is a label
+// that is not in labels.
+func serviceMissingPortLabel(service *api.Service, labels map[string]bool) bool {
+	if service == nil || service.PortLabel == "" || service.AddressMode == "driver" {
+		return false
+	}
+	if _, err := strconv.Atoi(service.PortLabel); err == nil {
+		return false
+	}
+	return !labels[service.PortLabel]
 }
 
-// HCL2JobParserConfig stores configuration options for the HCL2 jobspec parser.
-type HCL2JobParserConfig struct {
-	AllowFS bool
-	Vars    map[string]string
+// jobNoEligibleNodesWarning returns a warning if none of the ready and
+// eligible nodes match the datacenters, node pool and constraints of the job,
+// meaning it will never be placed.
+func jobNoEligibleNodesWarning(client *api.Client, job *api.Job) (*diag.Diagnostic, error) {
+	nodes, _, err := client.Nodes().List(&api.QueryOptions{
+		Params: map[string]string{"os": "true"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if jobTargetNodes(job, nodes) > 0 {
+		return nil, nil
+	}
+
+	return &diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Job %q doesn't match any eligible node", *job.ID),
+		Detail: fmt.Sprintf("None of the %d nodes in the cluster is ready, eligible and matches the "+
+			"job datacenters, node pool and constraints, so its allocations can't be placed. "+
+			"Constraints the provider can't evaluate, such as those on node metadata, are "+
+			"assumed to match.", len(nodes)),
+	}, nil
+}
+
+// jobTargetNodes returns the number of ready and eligible nodes that
+// match the datacenters, node pool and constraints of the job and of at least
+// one of its groups.
+func jobTargetNodes(job *api.Job, nodes []*api.NodeListStub) int {
+	pool := "default"
+	if job.NodePool != nil && *job.NodePool != "" {
+		pool = *job.NodePool
+	}
+
+	datacenters := job.Datacenters
+	if len(datacenters) == 0 {
+		datacenters = []string{"*"}
+	}
+
+	count := 0
+	for _, node := range nodes {
+		if node.Status != "ready" || node.SchedulingEligibility != "eligible" || node.Drain {
+			continue
+		}
+		if pool != "all" && node.NodePool != pool {
+			continue
+		}
+		if !nodeInDatacenters(node, datacenters) {
+			continue
+		}
+		if !nodeMatchesConstraints(node, job.Constraints) {
+			continue
+		}
+
+		for _, tg := range job.TaskGroups {
+			constraints := append([]*api.Constraint{}, tg.Constraints...)
+			for _, task := range tg.Tasks {
+				constraints = append(constraints, task.Constraints...)
+			}
+			if nodeMatchesConstraints(node, constraints) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+func nodeInDatacenters(node *api.NodeListStub, datacenters []string) bool {
+	for _, dc := range datacenters {
+		if ok, _ := path.Match(dc, node.Datacenter); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesConstraints evaluates equality constraints against the node.
+// Constraints with other operators or with targets that aren't available in
+// the node list are assumed to match.
+func nodeMatchesConstraints(node *api.NodeListStub, constraints []*api.Constraint) bool {
+	for _, c := range constraints {
+		l, lok := nodeConstraintTarget(node, c.LTarget)
+		r, rok := nodeConstraintTarget(node, c.RTarget)
+		if !lok || !rok {
+			continue
+		}
+
+		switch c.Operand {
+		case "", "=", "==", "is":
+			if l != r {
+				return false
+			}
+		case "!=", "not":
+			if l == r {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nodeConstraintTarget resolves a constraint target against the node. It
+// returns false if the target can't be resolved.
+func nodeConstraintTarget(node *api.NodeListStub, target string) (string, bool) {
+	if !strings.HasPrefix(target, "${") {
+		return target, true
+	}
+
+	switch target {
+	case "${node.unique.id}":
+		return node.ID, true
+	case "${node.unique.name}":
+		return node.Name, true
+	case "${node.datacenter}":
+		return node.Datacenter, true
+	case "${node.class}":
+		return node.NodeClass, true
+	case "${node.pool}":
+		return node.NodePool, true
+	}
+
+	if attr, ok := strings.CutPrefix(target, "${attr."); ok {
+		v, ok := node.Attributes[strings.TrimSuffix(attr, "}")]
+		return v, ok
+	}
+	return "", false
+}
+
+// deregisterChildJobs deregisters the jobs created from the parameterized or
+// periodic job parentID, such as "<parent>/dispatch-<id>" and
+// "<parent>/periodic-<id>".
+func deregisterChildJobs(client *api.Client, parentID string, purge bool, opts *api.WriteOptions) error {
+	children, _, err := client.Jobs().List(&api.QueryOptions{
+		Namespace: opts.Namespace,
+		Prefix:    parentID + "/",
+	})
+	if err != nil {
+		return fmt.Errorf("error listing child jobs of %q: %s", parentID, err)
+	}
+
+	for _, child := range children {
+		if child.ParentID != parentID {
+			continue
+		}
+
+		log.Printf("[DEBUG] deregistering child job %q of %q", child.ID, parentID)
+		_, _, err := client.Jobs().Deregister(child.ID, purge, opts)
+		if err != nil {
+			return fmt.Errorf("error deregistering child job %q: %s", child.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	id := d.Id()
+	opts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	log.Printf("[DEBUG] reading information for job %q in namespace %q", id, opts.Namespace)
+	job, _, err := client.Jobs().Info(id, opts)
+	if err != nil {
+		// As of Nomad 0.4.1, the API client returns an error for 404
+		// rather than a nil result, so we must check this way.
+		if strings.Contains(err.Error(), "404") {
+			log.Printf("[DEBUG] job %q does not exist, so removing", id)
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("error checking for job: %s", err)
+	}
+	log.Printf("[DEBUG] found job %q in namespace %q", *job.Name, *job.Namespace)
+
+	d.Set("name", job.ID)
+	d.Set("type", job.Type)
+	d.Set("region", job.Region)
+	d.Set("datacenters", job.Datacenters)
+	d.Set("task_groups", jobTaskGroupsRaw(job.TaskGroups))
+	d.Set("effective_deadline", effectiveDeadlineRaw(jobEffectiveDeadline(job)))
+	d.Set("parameterized", jobParameterizedRaw(job.ParameterizedJob))
+	d.Set("periodic", jobPeriodicRaw(job.Periodic))
+	d.Set("periodic_next_run", jobPeriodicNextRun(job.Periodic, time.Now()))
+	d.Set("namespace", job.Namespace)
+	if job.JobModifyIndex != nil {
+		d.Set("modify_index", strconv.FormatUint(*job.JobModifyIndex, 10))
+	} else {
+		d.Set("modify_index", "0")
+	}
+	if job.CreateIndex != nil {
+		d.Set("create_index", strconv.FormatUint(*job.CreateIndex, 10))
+	} else {
+		d.Set("create_index", "0")
+	}
+	d.Set("submit_time", jobSubmitTime(job.SubmitTime))
+	d.Set("status", job.Status)
+
+	versions, _, _, err := client.Jobs().Versions(id, false, opts)
+	if err != nil {
+		log.Printf("[WARN] error reading versions of job %q, will keep the version count in state: %v", id, err)
+	} else {
+		d.Set("version_count", len(versions))
+		d.Set("previous_stable_version", jobPreviousStableVersion(versions, job.Version))
+		if warning := jobVersionCountWarning(id, len(versions), d.Get("max_versions").(int)); warning != nil {
+			log.Printf("[WARN] %s: %s", warning.Summary, warning.Detail)
+		}
+	}
+	d.Set("stopped", job.Stop)
+	d.Set("dispatched", job.Dispatched)
+	d.Set("parent_id", job.ParentID)
+
+	deployment, _, err := client.Jobs().LatestDeployment(id, opts)
+	if err != nil {
+		log.Printf("[WARN] error reading latest deployment for job %q, will return empty canary status: %v", id, err)
+	}
+	d.Set("canary_status", jobCanaryStatusRaw(deployment))
+
+	lastChildStatus := ""
+	if job.IsPeriodic() {
+		children, _, err := client.Jobs().List(&api.QueryOptions{
+			Namespace: opts.Namespace,
+			Prefix:    *job.ID + "/periodic-",
+		})
+		if err != nil {
+			log.Printf("[WARN] error listing child jobs of periodic job %q: %v", id, err)
+		} else {
+			lastChildStatus = jobPeriodicLastChildStatus(*job.ID, children)
+		}
+	}
+	d.Set("periodic_last_child_status", lastChildStatus)
+
+	if d.Get("read_allocation_ids").(bool) {
+		allocStubs, _, err := client.Jobs().Allocations(id, false, opts)
+		if err != nil {
+			log.Printf("[WARN] error listing allocations for Job %q, will return empty list", id)
+		}
+		allocIDs := make([]string, 0, len(allocStubs))
+		for _, a := range allocStubs {
+			allocIDs = append(allocIDs, a.ID)
+		}
+
+		d.Set("allocation_ids", allocIDs)
+	} else {
+		d.Set("allocation_ids", nil)
+	}
+
+	trackSubmission := resourceJobTrackSubmission(d, providerConfig)
+	d.Set("track_submission", trackSubmission)
+	if !trackSubmission {
+		log.Printf("[DEBUG] submission tracking is disabled for job %q", id)
+		return nil
+	}
+
+	// The submission of a partially managed job may come from another
+	// configuration managing other groups, so it can't be compared with the
+	// jobspec.
+	if len(resourceJobManagedGroups(d)) > 0 {
+		log.Printf("[DEBUG] job %q is partially managed, skipping jobspec source comparison", id)
+		return nil
+	}
+
+	// Update jobspec submission data if available.
+	// Safely ignore errors as this is an optional step: submissions may have
+	// been pruned or not be retained by the cluster, in which case the jobspec
+	// in state is kept and drift is detected from the job structure only.
+	sub, _, err := client.Jobs().Submission(*job.ID, int(*job.Version), opts)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			log.Printf("[DEBUG] no submission found for version %d of job %q, skipping jobspec source comparison", *job.Version, id)
+		} else {
+			log.Printf("[WARN] failed to read job submission, skipping jobspec source comparison: %v", err)
+		}
+	} else {
+		err := resourceJobReadSubmission(sub, d, meta)
+		if err != nil {
+			log.Printf("[WARN] failed to update job submission: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// resourceJobTrackSubmission returns whether the job submission should be
+// read back from Nomad. The value in state is used if there is one, otherwise
+// (such as when importing) the provider default applies.
+func resourceJobTrackSubmission(d *schema.ResourceData, providerConfig ProviderConfig) bool {
+	//lint:ignore SA1019 GetOkExists is needed to tell an unset value from false.
+	if v, ok := d.GetOkExists("track_submission"); ok {
+		return v.(bool)
+	}
+	return providerConfig.trackJobSubmissions
+}
+
+// jobCanaryStatusRaw returns the canary status of the task groups of the
+// deployment that have canaries, sorted by group name.
+func jobCanaryStatusRaw(deployment *api.Deployment) []interface{} {
+	if deployment == nil {
+		return nil
+	}
+
+	groups := make([]string, 0, len(deployment.TaskGroups))
+	for name, state := range deployment.TaskGroups {
+		if state == nil || state.DesiredCanaries == 0 {
+			continue
+		}
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	result := make([]interface{}, 0, len(groups))
+	for _, name := range groups {
+		state := deployment.TaskGroups[name]
+		result = append(result, map[string]interface{}{
+			"group":            name,
+			"desired_canaries": state.DesiredCanaries,
+			"placed_canaries":  len(state.PlacedCanaries),
+			"healthy_canaries": jobHealthyCanaries(state),
+		})
+	}
+	return result
+}
+
+// jobPlanAnnotationsRaw summarizes the desired updates and placement failures
+// of each task group in the plan response, sorted by task group name.
+func jobPlanAnnotationsRaw(resp *api.JobPlanResponse) []interface{} {
+	if resp == nil {
+		return nil
+	}
+
+	var desired map[string]*api.DesiredUpdates
+	if resp.Annotations != nil {
+		desired = resp.Annotations.DesiredTGUpdates
+	}
+
+	groups := make([]string, 0, len(desired))
+	for name := range desired {
+		groups = append(groups, name)
+	}
+	for name := range resp.FailedTGAllocs {
+		if _, ok := desired[name]; !ok {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+
+	taskGroups := make([]interface{}, 0, len(groups))
+	for _, name := range groups {
+		updates := desired[name]
+		if updates == nil {
+			updates = &api.DesiredUpdates{}
+		}
+		failed := 0
+		if metric := resp.FailedTGAllocs[name]; metric != nil {
+			// the first failure isn't counted as coalesced
+			failed = metric.CoalescedFailures + 1
+		}
+		taskGroups = append(taskGroups, map[string]interface{}{
+			"group":              name,
+			"place":              int(updates.Place),
+			"stop":               int(updates.Stop),
+			"migrate":            int(updates.Migrate),
+			"ignore":             int(updates.Ignore),
+			"in_place_update":    int(updates.InPlaceUpdate),
+			"destructive_update": int(updates.DestructiveUpdate),
+			"canary":             int(updates.Canary),
+			"preemptions":        int(updates.Preemptions),
+			"failed_placements":  failed,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"warnings":    resp.Warnings,
+			"task_groups": taskGroups,
+		},
+	}
+}
+
+// jobHealthyCanaries returns the number of healthy canaries of the deployment
+// state. Once the canaries are promoted their allocations are counted as part
+// of the healthy allocations instead.
+func jobHealthyCanaries(state *api.DeploymentState) int {
+	if state.Promoted {
+		return min(state.HealthyAllocs, len(state.PlacedCanaries))
+	}
+	return state.HealthyAllocs
+}
+
+func resourceJobReadSubmission(sub *api.JobSubmission, d *schema.ResourceData, meta any) error {
+	if sub == nil {
+		return nil
+	}
+
+	if sub.Source != "" {
+		d.Set("jobspec", sub.Source)
+	}
+
+	if sub.Format == "hcl2" {
+		var err error
+		var hcl2Config HCL2JobParserConfig
+
+		hcl2, ok := d.GetOk("hcl2")
+		if ok {
+			hcl2Config, err = parseHCL2JobParserConfig(hcl2)
+			if err != nil {
+				return fmt.Errorf("failed to parse HCL2 config: %v", err)
+			}
+		} else {
+			// Use default values if hcl2 is not set.
+			hcl2Config = HCL2JobParserConfig{
+				AllowFS: false,
+				Enabled: true,
+			}
+		}
+
+		// Only update hcl2 if there are changes to variables to avoid
+		// unnecessary updates if hcl2 is not set.
+		if !maps.Equal(sub.VariableFlags, hcl2Config.Vars) {
+			hcl2Config.Vars = sub.VariableFlags
+			d.Set("hcl2", flattenHCL2JobParserConfig(hcl2Config))
+		}
+
+		// The content of the variable files is submitted with the jobspec.
+		d.Set("hcl2_var_files_digest", hcl2VarFilesDigest(sub.Variables))
+	}
+
+	return nil
+}
+
+func resourceJobCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	log.Printf("[DEBUG] resourceJobCustomizeDiff")
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	// Resources that don't set track_submission follow the provider default.
+	if raw := d.GetRawConfig(); raw.IsKnown() && !raw.IsNull() && raw.GetAttr("track_submission").IsNull() {
+		d.SetNew("track_submission", providerConfig.trackJobSubmissions)
+	}
+
+	if !d.NewValueKnown("jobspec") {
+		d.SetNewComputed("name")
+		d.SetNewComputed("modify_index")
+		d.SetNewComputed("create_index")
+		d.SetNewComputed("submit_time")
+		d.SetNewComputed("version_count")
+		d.SetNewComputed("previous_stable_version")
+		d.SetNewComputed("job_json")
+		d.SetNewComputed("namespace")
+		d.SetNewComputed("type")
+		d.SetNewComputed("region")
+		d.SetNewComputed("datacenters")
+		d.SetNewComputed("allocation_ids")
+		d.SetNewComputed("task_groups")
+		d.SetNewComputed("effective_deadline")
+		d.SetNewComputed("parameterized")
+		d.SetNewComputed("periodic")
+		d.SetNewComputed("periodic_next_run")
+		d.SetNewComputed("periodic_last_child_status")
+		d.SetNewComputed("deployment_id")
+		d.SetNewComputed("deployment_status")
+		d.SetNewComputed("multiregion_deployment_status")
+		d.SetNewComputed("canary_status")
+		d.SetNewComputed("plan_annotations")
+		d.SetNewComputed("status")
+		return nil
+	}
 
-	// Deprecated: Starting in v2.0.0 the provider assumes HCL2 parsing by
-	// default. This field should only be used to update the `hcl2` attribute
-	// in state without causing a diff.
-	Enabled bool
-}
+	if d.Get("status").(string) == "dead" && d.Get("rerun_if_dead").(bool) {
+		// a job stopped outside of Terraform is registered again
+		if d.Get("stopped").(bool) {
+			d.SetNew("stopped", false)
+		}
+		d.SetNewComputed("status")
+		d.SetNewComputed("submit_time")
+		d.SetNewComputed("version_count")
+		d.SetNewComputed("previous_stable_version")
+		if d.Get("purge_before_rerun").(bool) {
+			// the purged job is created again with a new index
+			d.SetNewComputed("create_index")
+		}
+	}
 
-// ResourceFieldGetter are able to retrieve field values.
-// Examples: *schema.ResourceData and *schema.ResourceDiff
-type ResourceFieldGetter interface {
-	Get(string) interface{}
-}
+	// The variables of restart_on are compared with the indexes stored on the
+	// last apply, so their changes are shown in the plan.
+	if d.Id() != "" && d.NewValueKnown("restart_on") {
+		if paths := restartOnVariablePaths(d); len(paths) > 0 {
+			indexes, err := variableModifyIndexes(client, d.Get("namespace").(string), paths)
+			if err != nil {
+				log.Printf("[WARN] failed to read variables of restart_on: %s", err)
+			} else {
+				d.SetNew("variable_modify_indexes", indexes)
+			}
+		} else if len(d.Get("variable_modify_indexes").(map[string]interface{})) > 0 {
+			d.SetNew("variable_modify_indexes", map[string]string{})
+		}
+	}
 
-func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
-	timeout := d.Timeout(schema.TimeoutCreate)
-	if !d.IsNewResource() {
-		timeout = d.Timeout(schema.TimeoutUpdate)
-		d.Partial(true)
+	// The variable files are only referenced by their paths, so the digest
+	// of their content is compared to detect changes to the files.
+	if d.NewValueKnown("hcl2") {
+		jobParserConfig, err := parseJobParserConfig(d)
+		if err != nil {
+			return err
+		}
+		if !jobParserConfig.JSON.Enabled && !jobParserConfig.HCL1.Enabled {
+			content, err := readHCL2VarFiles(jobParserConfig.HCL2.VarFiles)
+			if err != nil {
+				return err
+			}
+			if digest := hcl2VarFilesDigest(content); digest != d.Get("hcl2_var_files_digest").(string) {
+				d.SetNew("hcl2_var_files_digest", digest)
+			}
+		}
 	}
 
-	providerConfig := meta.(ProviderConfig)
-	client := providerConfig.client
+	oldSpecRaw, newSpecRaw := d.GetChange("jobspec")
 
-	// Get the jobspec itself.
-	jobspecRaw := d.Get("jobspec").(string)
+	if jobspecEqual("jobspec", oldSpecRaw.(string), newSpecRaw.(string), d) &&
+		!d.HasChanges("datacenters", "env_override", "restart_override", "reschedule_override", "vault_override", "hcl2_var_files_digest") &&
+		!jobDatacentersDrifted(d, newSpecRaw.(string)) {
+		// nothing to do!
+		return nil
+	}
 
 	// Read job parsing config.
 	jobParserConfig, err := parseJobParserConfig(d)
@@ -397,670 +4473,1279 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 		vaultToken = *providerConfig.vaultToken
 	}
 
-	// Parse jobspec.
-	job, err := parseJobspec(jobspecRaw, jobParserConfig, &vaultToken, &consulToken)
+	// Parse jobspec
+	// Catch syntax errors client-side during plan
+	job, err := parseJobspec(newSpecRaw.(string), jobParserConfig, &vaultToken, &consulToken)
 	if err != nil {
 		return err
 	}
+	if err := applyJobOverrides(job, d); err != nil {
+		return err
+	}
+	applyProviderJobDefaults(job, providerConfig)
+	if err := validateJobConsulPartitions(job, providerConfig); err != nil {
+		return err
+	}
+	if err := validateJobTaskResources(job); err != nil {
+		return err
+	}
 
+	defaultNamespace := "default"
 	if job.Namespace == nil || *job.Namespace == "" {
-		defaultNamespace := "default"
 		job.Namespace = &defaultNamespace
 	}
 
-	// Register the job
-	wantModifyIndexStrI, _ := d.GetChange("modify_index")
-	wantModifyIndex, err := strconv.ParseUint(wantModifyIndexStrI.(string), 10, 64)
-	if err != nil {
-		wantModifyIndex = 0
+	if !d.Get("manage_count").(bool) {
+		previous := parsePreviousJobspec(oldSpecRaw.(string), jobParserConfig)
+		if err := preserveTaskGroupCounts(client, job, previous); err != nil {
+			log.Printf("[WARN] failed to read current task group counts: %s", err)
+		}
 	}
 
-	sub := &api.JobSubmission{
-		Source:        jobspecRaw,
-		Format:        "hcl2",
-		VariableFlags: jobParserConfig.HCL2.Vars,
+	if managed := resourceJobManagedGroups(d); len(managed) > 0 {
+		if err := validateManagedTaskGroups(job, managed); err != nil {
+			return err
+		}
+		if err := mergeManagedTaskGroups(client, job, managed); err != nil {
+			log.Printf("[WARN] failed to read the task groups not managed by the job: %s", err)
+		}
 	}
-	switch {
-	case jobParserConfig.JSON.Enabled:
-		sub.Format = "json"
-	case jobParserConfig.HCL1.Enabled:
-		sub.Format = "hcl1"
+
+	// CustomizeDiff can't return warnings, so log them during plan. They are
+	// also returned as diagnostics once the job is registered. The jobspec
+	// warnings are already returned by its validation when it can parse the
+	// jobspec on its own.
+	if _, ok := parseJobspecForWarnings(newSpecRaw.(string)); !ok {
+		for _, warning := range jobspecStaticWarnings(job) {
+			log.Printf("[WARN] %s: %s", warning.Summary, warning.Detail)
+		}
+	}
+	if warning := jobRegionWarning(providerConfig, job); warning != nil {
+		if d.Get("fail_on_region_mismatch").(bool) {
+			return fmt.Errorf("%s: %s", warning.Summary, warning.Detail)
+		}
+		log.Printf("[WARN] %s: %s", warning.Summary, warning.Detail)
+	}
+	failOnNoEligibleNodes := d.Get("fail_on_no_eligible_nodes").(bool)
+	if d.Get("warn_on_no_eligible_nodes").(bool) || failOnNoEligibleNodes {
+		warning, err := jobNoEligibleNodesWarning(client, job)
+		if err != nil {
+			log.Printf("[WARN] failed to list nodes eligible for job %q: %s", *job.ID, err)
+		} else if warning != nil && failOnNoEligibleNodes {
+			return fmt.Errorf("%s: %s", warning.Summary, warning.Detail)
+		} else if warning != nil {
+			log.Printf("[WARN] %s: %s", warning.Summary, warning.Detail)
+		}
 	}
 
-	resp, _, err := client.Jobs().RegisterOpts(job, &api.RegisterOptions{
+	resp, _, err := client.Jobs().PlanOpts(job, &api.PlanOptions{
+		Diff:           false,
 		PolicyOverride: d.Get("policy_override").(bool),
-		ModifyIndex:    wantModifyIndex,
-		Submission:     sub,
 	}, &api.WriteOptions{
 		Namespace: *job.Namespace,
 	})
 	if err != nil {
-		return fmt.Errorf("error applying jobspec: %s", err)
+		log.Printf("[WARN] failed to validate Nomad plan: %s", err)
 	}
+	d.SetNew("plan_annotations", jobPlanAnnotationsRaw(resp))
 
-	if !d.IsNewResource() {
-		d.Partial(false)
+	// If we were able to successfully plan then we can safely populate our
+	// diff with new values based on the job object we got from parsing,
+	// causing the Terraform diff to correctly reflect the planned changes
+	// to the subset of job attributes we include in our schema.
+
+	d.SetNew("name", job.ID)
+	d.SetNew("type", job.Type)
+	d.SetNew("region", job.Region)
+	if d.NewValueKnown("datacenters") {
+		d.SetNew("datacenters", job.Datacenters)
 	}
+	d.SetNew("status", job.Status)
 
-	log.Printf("[DEBUG] job '%s' registered in namespace '%s'", *job.ID, *job.Namespace)
-	d.SetId(*job.ID)
-	d.Set("name", job.ID)
-	d.Set("namespace", job.Namespace)
-	d.Set("modify_index", strconv.FormatUint(resp.JobModifyIndex, 10))
+	// If the identity has changed and the config asks us to deregister on identity
+	// change then the id field "forces new resource".
+	if d.Get("namespace").(string) != *job.Namespace {
+		d.SetNew("namespace", job.Namespace)
+		d.SetNewComputed("create_index")
+		if d.Id() != "" && d.Get("preserve_across_namespace_change").(bool) {
+			log.Printf("[DEBUG] allowing namespace change as update because preserve_across_namespace_change is set")
+		} else {
+			log.Printf("[DEBUG] namespace change forces new resource")
+			d.ForceNew("namespace")
+		}
+	} else if d.Id() != *job.ID {
+		// a job with a new ID is created at a new index
+		d.SetNewComputed("create_index")
+		if d.Get("deregister_on_id_change").(bool) {
+			log.Printf("[DEBUG] name change forces new resource because deregister_on_id_change is set")
+			d.ForceNew("id")
+			d.ForceNew("name")
+		} else {
+			log.Printf("[DEBUG] allowing name change as update because deregister_on_id_change is not set")
+		}
+	} else {
+		d.SetNew("namespace", job.Namespace)
 
-	if d.Get("detach") == false && resp.EvalID != "" {
-		log.Printf("[DEBUG] will monitor scheduling/deployment of job '%s' in namespace '%s'", *job.ID, *job.Namespace)
-		deployment, err := monitorDeployment(client, timeout, *job.Namespace, resp.EvalID)
+		// If the identity (namespace+name) _isn't_ changing, then we require consistency of the
+		// job modify index to ensure that the "old" part of our diff
+		// will show what Nomad currently knows.
+		wantModifyIndexStr := d.Get("modify_index").(string)
+		wantModifyIndex, err := strconv.ParseUint(wantModifyIndexStr, 10, 64)
 		if err != nil {
-			return fmt.Errorf(
-				"error waiting for job '%s' to schedule/deploy successfully: %s",
-				*job.ID, err)
+			// should never happen, because we always write with FormatUint
+			// in Read above.
+			return fmt.Errorf("invalid modify_index in state: %s", err)
 		}
-		if deployment != nil {
-			d.Set("deployment_id", deployment.ID)
-			d.Set("deployment_status", deployment.Status)
-		} else {
-			d.Set("deployment_id", nil)
-			d.Set("deployment_status", nil)
+
+		if resp != nil && resp.JobModifyIndex != wantModifyIndex {
+			// Should rarely happen, but might happen if there was a concurrent
+			// other process writing to Nomad since our Read call.
+			return fmt.Errorf("job modify index has changed since last refresh")
+		}
+	}
+
+	// We know that applying changes here will change the modify index
+	// _somehow_, but we won't know how much it will increment until
+	// after we complete registration.
+	d.SetNewComputed("modify_index")
+	// nor when the new version is submitted, or how many versions are kept
+	d.SetNewComputed("submit_time")
+	d.SetNewComputed("version_count")
+	d.SetNewComputed("previous_stable_version")
+	// similarly, we won't know the allocation ids until after the job registration eval
+	d.SetNewComputed("allocation_ids")
+	// or whether the update creates a new deployment with canaries
+	d.SetNewComputed("canary_status")
+	// or the exit codes of the tasks of a monitored batch job
+	if !d.Get("detach").(bool) && job.Type != nil && *job.Type == api.JobTypeBatch {
+		d.SetNewComputed("batch_exit_code")
+	}
+
+	if d.NewValueKnown("datacenters") {
+		jobJSON, err := canonicalJobJSON(job)
+		if err != nil {
+			return err
 		}
+		d.SetNew("job_json", jobJSON)
+	} else {
+		// the datacenters override is only known on apply
+		d.SetNewComputed("job_json")
 	}
 
-	return resourceJobRead(d, meta) // populate other computed attributes
+	// Canonicalize the job so the planned task groups include the same
+	// defaults (such as the CSI plugin health timeout) that Nomad will store.
+	job.Canonicalize()
+	d.SetNew("task_groups", jobTaskGroupsRaw(job.TaskGroups))
+	d.SetNew("effective_deadline", effectiveDeadlineRaw(jobEffectiveDeadline(job)))
+	d.SetNew("parameterized", jobParameterizedRaw(job.ParameterizedJob))
+	d.SetNew("periodic", jobPeriodicRaw(job.Periodic))
+	d.SetNewComputed("periodic_next_run")
+
+	return nil
 }
 
-// monitorDeployment monitors the evalution(s) from a job create/update and,
-// if they result in a deployment, monitors that deployment until completion.
-func monitorDeployment(client *api.Client, timeout time.Duration, namespace string, initialEvalID string) (*api.Deployment, error) {
+func parseJobParserConfig(d ResourceFieldGetter) (JobParserConfig, error) {
+	config := JobParserConfig{}
 
-	stateConf := &resource.StateChangeConf{
-		Pending:    []string{MonitoringEvaluation},
-		Target:     []string{EvaluationComplete},
-		Refresh:    evaluationStateRefreshFunc(client, namespace, initialEvalID),
-		Timeout:    timeout,
-		Delay:      0,
-		MinTimeout: 3 * time.Second,
+	// Read JSON parser configuration.
+	jsonEnabled := d.Get("json").(bool)
+	config.JSON = JSONJobParserConfig{
+		Enabled: jsonEnabled,
 	}
 
-	state, err := stateConf.WaitForState()
+	// Read HCL1 parser configuration.
+	hclEnabled := d.Get("hcl1").(bool)
+	config.HCL1 = HCL1JobParserConfig{
+		Enabled: hclEnabled,
+	}
+
+	// Read HCL2 parser configuration.
+	hcl2Config, err := parseHCL2JobParserConfig(d.Get("hcl2"))
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for evaluation: %s", err)
+		return config, err
 	}
+	config.HCL2 = hcl2Config
 
-	evaluation := state.(*api.Evaluation)
-	if evaluation.DeploymentID == "" {
-		log.Printf("[WARN] job has been scheduled, but there is no deployment to monitor")
-		return nil, nil
+	// JSON and HCL1 parsing are conflicting options.
+	if config.JSON.Enabled && config.HCL1.Enabled {
+		return config, fmt.Errorf("invalid combination. json is %t and hcl1 is %t", config.JSON.Enabled, config.HCL1.Enabled)
 	}
 
-	stateConf = &resource.StateChangeConf{
-		Pending:    []string{MonitoringDeployment},
-		Target:     []string{DeploymentSuccessful},
-		Refresh:    deploymentStateRefreshFunc(client, namespace, evaluation.DeploymentID),
-		Timeout:    timeout,
-		Delay:      0,
-		MinTimeout: 5 * time.Second,
+	return config, nil
+}
+
+func parseHCL2JobParserConfig(raw interface{}) (HCL2JobParserConfig, error) {
+	config := HCL2JobParserConfig{}
+
+	// `hcl2` must be a list with only one element.
+	hcl2List, ok := raw.([]interface{})
+	if !ok || len(hcl2List) > 1 {
+		return config, fmt.Errorf("failed to unpack hcl2 configuration block")
+	}
+
+	// If the list is empty, it means we don't have a `hcl2` block.
+	if len(hcl2List) == 0 {
+		return config, nil
+	}
+
+	// The only element in the list must be a map.
+	hcl2Map, ok := hcl2List[0].(map[string]interface{})
+	if !ok {
+		return config, nil
+	}
+
+	// Read map fields into parser config struct.
+	if allowFS, ok := hcl2Map["allow_fs"].(bool); ok {
+		config.AllowFS = allowFS
+	}
+	if enabled, ok := hcl2Map["enabled"].(bool); ok {
+		config.Enabled = enabled
+	}
+	if vars, ok := hcl2Map["vars"].(map[string]interface{}); ok {
+		config.Vars = make(map[string]string)
+		for k, v := range vars {
+			config.Vars[k] = v.(string)
+		}
+	}
+	if varFiles, ok := hcl2Map["var_files"].([]interface{}); ok {
+		for _, f := range varFiles {
+			if file, ok := f.(string); ok && file != "" {
+				config.VarFiles = append(config.VarFiles, file)
+			}
+		}
+	}
+
+	return config, nil
+}
+
+func flattenHCL2JobParserConfig(c HCL2JobParserConfig) []any {
+	return []any{map[string]any{
+		"allow_fs":  c.AllowFS,
+		"enabled":   c.Enabled,
+		"vars":      c.Vars,
+		"var_files": c.VarFiles,
+	}}
+}
+
+func parseJobspec(raw string, config JobParserConfig, vaultToken *string, consulToken *string) (*api.Job, error) {
+	var job *api.Job
+	var err error
+
+	switch {
+	case config.JSON.Enabled:
+		job, err = parseJSONJobspec(raw)
+	case config.HCL1.Enabled:
+		job, err = jobspec.Parse(strings.NewReader(raw))
+	default:
+		job, err = parseHCL2Jobspec(raw, config.HCL2)
 	}
 
-	state, err = stateConf.WaitForState()
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for evaluation: %s", err)
+		return nil, fmt.Errorf("error parsing jobspec: %s", err)
 	}
-	return state.(*api.Deployment), nil
-}
 
-// evaluationStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
-// the evaluation(s) from a job create/update
-func evaluationStateRefreshFunc(client *api.Client, namespace string, initialEvalID string) resource.StateRefreshFunc {
+	// If job is empty after parsing, the input is not a valid Nomad job.
+	if job == nil || reflect.DeepEqual(job, &api.Job{}) {
+		return nil, fmt.Errorf("error parsing jobspec: input JSON is not a valid Nomad jobspec")
+	}
 
-	// evalID is the evaluation that we are currently monitoring. This will change
-	// along with follow-up evaluations.
-	evalID := initialEvalID
+	// Inject the Vault and Consul tokens
+	job.VaultToken = vaultToken
+	job.ConsulToken = consulToken
 
-	return func() (interface{}, string, error) {
-		// monitor the eval
-		log.Printf("[DEBUG] monitoring evaluation '%s' in namespace '%s'", evalID, namespace)
-		eval, _, err := client.Evaluations().Info(evalID, &api.QueryOptions{
-			Namespace: namespace,
-		})
-		if err != nil {
-			log.Printf("[ERROR] error on Evaluation.Info during deploymentStateRefresh: %s", err)
-			return nil, "", err
+	return job, nil
+}
+
+// applyProviderJobDefaults sets the values from the provider configuration
+// that should be used when the jobspec doesn't define them.
+func applyProviderJobDefaults(job *api.Job, providerConfig ProviderConfig) {
+	if job.Priority == nil && providerConfig.defaultJobPriority > 0 {
+		job.Priority = pointer.Of(providerConfig.defaultJobPriority)
+	}
+
+	for _, tg := range job.TaskGroups {
+		if tg.Consul != nil {
+			if tg.Consul.Namespace == "" {
+				tg.Consul.Namespace = providerConfig.defaultConsulNamespace
+			}
+			if tg.Consul.Partition == "" {
+				tg.Consul.Partition = providerConfig.defaultConsulPartition
+			}
 		}
 
-		var state string
-		switch eval.Status {
-		case "complete":
-			// Monitor the next eval in the chain, if present
-			log.Printf("[DEBUG] evaluation '%v' in namespace '%s' complete", eval.ID, namespace)
-			if eval.NextEval != "" {
-				log.Printf("[DEBUG] will monitor follow-up eval '%v'", eval.ID)
-				evalID = eval.NextEval
-				state = MonitoringEvaluation
-			} else {
-				state = EvaluationComplete
+		for _, task := range tg.Tasks {
+			if task.Consul != nil {
+				if task.Consul.Namespace == "" {
+					task.Consul.Namespace = providerConfig.defaultConsulNamespace
+				}
+				if task.Consul.Partition == "" {
+					task.Consul.Partition = providerConfig.defaultConsulPartition
+				}
+			}
+			if task.Vault != nil && (task.Vault.Namespace == nil || *task.Vault.Namespace == "") &&
+				providerConfig.defaultVaultNamespace != "" {
+				task.Vault.Namespace = pointer.Of(providerConfig.defaultVaultNamespace)
 			}
-		case "failed", "cancelled":
-			return nil, "", fmt.Errorf("evaluation failed: %v", eval.StatusDescription)
-		default:
-			state = MonitoringEvaluation
 		}
-		return eval, state, nil
 	}
 }
 
-// deploymentStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
-// the deployment from a job create/update
-func deploymentStateRefreshFunc(client *api.Client, namespace string, deploymentID string) resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
-		// monitor the deployment
-		var state string
-		deployment, _, err := client.Deployments().Info(deploymentID, &api.QueryOptions{
-			Namespace: namespace,
-		})
-		if err != nil {
-			log.Printf("[ERROR] error on Deployment.Info during deploymentStateRefresh: %s", err)
-			return nil, "", err
-		}
-		switch deployment.Status {
-		case "successful":
-			log.Printf("[DEBUG] deployment '%s' in namespace '%s' successful", deployment.ID, namespace)
-			state = DeploymentSuccessful
-		case "failed", "cancelled":
-			log.Printf("[DEBUG] deployment unsuccessful: %s", deployment.StatusDescription)
-			return deployment, "",
-				fmt.Errorf("deployment '%s' terminated with status '%s': '%s'",
-					deployment.ID, deployment.Status, deployment.StatusDescription)
-		default:
-			// don't overwhelm the API server
-			state = MonitoringDeployment
-		}
-		return deployment, state, nil
+// applyJobOverrides applies the override blocks of the resource to the job.
+// They run before the provider defaults so the blocks they add get them too.
+func applyJobOverrides(job *api.Job, d ResourceConfigGetter) error {
+	if datacenters := jobDatacentersOverride(d.GetRawConfig()); datacenters != nil {
+		job.Datacenters = datacenters
+	}
+	if err := applyEnvOverrides(job, d.Get("env_override").([]interface{})); err != nil {
+		return err
+	}
+	if err := applyRestartOverrides(job, d.Get("restart_override").([]interface{})); err != nil {
+		return err
+	}
+	if err := applyRescheduleOverrides(job, d.Get("reschedule_override").([]interface{})); err != nil {
+		return err
 	}
+	return applyVaultOverrides(job, d.Get("vault_override").([]interface{}))
 }
 
-func resourceJobDeregister(d *schema.ResourceData, meta interface{}) error {
-	providerConfig := meta.(ProviderConfig)
-	client := providerConfig.client
-
-	// If deregistration is disabled, then do nothing
-	deregister_on_destroy := d.Get("deregister_on_destroy").(bool)
-	if !deregister_on_destroy {
-		log.Printf(
-			"[WARN] job %q will not deregister since "+
-				"'deregister_on_destroy' is %t", d.Id(), deregister_on_destroy)
+// jobDatacentersOverride returns the datacenters set in the configuration of
+// the resource, or nil if they are not set or not known yet. datacenters is
+// also computed from the jobspec, so the configuration is used to tell the
+// override apart from the value in state.
+func jobDatacentersOverride(config cty.Value) []string {
+	if !config.IsKnown() || config.IsNull() {
 		return nil
 	}
-
-	id := d.Id()
-	log.Printf("[DEBUG] deregistering job: %q", id)
-	opts := &api.WriteOptions{
-		Namespace: d.Get("namespace").(string),
-	}
-	if opts.Namespace == "" {
-		opts.Namespace = "default"
-	}
-	purge := d.Get("purge_on_destroy").(bool)
-	_, _, err := client.Jobs().Deregister(id, purge, opts)
-	if err != nil {
-		return fmt.Errorf("error deregistering job: %s", err)
+	raw := config.GetAttr("datacenters")
+	if !raw.IsWhollyKnown() || raw.IsNull() {
+		return nil
 	}
 
-	return nil
+	datacenters := make([]string, 0, raw.LengthInt())
+	for it := raw.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		datacenters = append(datacenters, v.AsString())
+	}
+	return datacenters
 }
 
-func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
-	providerConfig := meta.(ProviderConfig)
-	client := providerConfig.client
-
-	id := d.Id()
-	opts := &api.QueryOptions{
-		Namespace: d.Get("namespace").(string),
+// jobDatacentersDrifted returns whether the datacenters of the job differ from
+// the ones of the jobspec while the configuration doesn't override them. This
+// happens when the datacenters override is removed, or when the datacenters
+// are changed outside of Terraform, and the job must be registered again with
+// the datacenters of the jobspec.
+func jobDatacentersDrifted(d *schema.ResourceDiff, jobspec string) bool {
+	if d.Id() == "" || !d.NewValueKnown("datacenters") {
+		return false
 	}
-	if opts.Namespace == "" {
-		opts.Namespace = "default"
+	config := d.GetRawConfig()
+	if !config.IsKnown() || config.IsNull() || !config.GetAttr("datacenters").IsNull() {
+		return false
 	}
-	log.Printf("[DEBUG] reading information for job %q in namespace %q", id, opts.Namespace)
-	job, _, err := client.Jobs().Info(id, opts)
-	if err != nil {
-		// As of Nomad 0.4.1, the API client returns an error for 404
-		// rather than a nil result, so we must check this way.
-		if strings.Contains(err.Error(), "404") {
-			log.Printf("[DEBUG] job %q does not exist, so removing", id)
-			d.SetId("")
-			return nil
-		}
 
-		return fmt.Errorf("error checking for job: %s", err)
+	jobParserConfig, err := parseJobParserConfig(d)
+	if err != nil {
+		return false
+	}
+	job, err := parseJobspec(jobspec, jobParserConfig, nil, nil)
+	if err != nil {
+		// parsing errors are reported when planning the registration
+		return true
 	}
-	log.Printf("[DEBUG] found job %q in namespace %q", *job.Name, *job.Namespace)
 
-	d.Set("name", job.ID)
-	d.Set("type", job.Type)
-	d.Set("region", job.Region)
-	d.Set("datacenters", job.Datacenters)
-	d.Set("task_groups", jobTaskGroupsRaw(job.TaskGroups))
-	d.Set("namespace", job.Namespace)
-	if job.JobModifyIndex != nil {
-		d.Set("modify_index", strconv.FormatUint(*job.JobModifyIndex, 10))
-	} else {
-		d.Set("modify_index", "0")
+	// Nomad registers jobs without datacenters in all of them.
+	datacenters := job.Datacenters
+	if len(datacenters) == 0 {
+		datacenters = []string{"*"}
 	}
-	d.Set("status", job.Status)
+	want := schema.NewSet(schema.HashString, nil)
+	for _, dc := range datacenters {
+		want.Add(dc)
+	}
+	return !d.Get("datacenters").(*schema.Set).Equal(want)
+}
 
-	if d.Get("read_allocation_ids").(bool) {
-		allocStubs, _, err := client.Jobs().Allocations(id, false, opts)
-		if err != nil {
-			log.Printf("[WARN] error listing allocations for Job %q, will return empty list", id)
+// applyEnvOverrides merges the environment variables of the env_override
+// blocks into the tasks of the job.
+func applyEnvOverrides(job *api.Job, overrides []interface{}) error {
+	for _, raw := range overrides {
+		override := raw.(map[string]interface{})
+		groupName := override["group"].(string)
+		taskName := override["task"].(string)
+
+		tg := job.LookupTaskGroup(groupName)
+		if tg == nil {
+			return fmt.Errorf("env_override: group %q not found in job", groupName)
 		}
-		allocIDs := make([]string, 0, len(allocStubs))
-		for _, a := range allocStubs {
-			allocIDs = append(allocIDs, a.ID)
+		var task *api.Task
+		for _, t := range tg.Tasks {
+			if t.Name == taskName {
+				task = t
+				break
+			}
+		}
+		if task == nil {
+			return fmt.Errorf("env_override: task %q not found in group %q", taskName, groupName)
 		}
 
-		d.Set("allocation_ids", allocIDs)
-	} else {
-		d.Set("allocation_ids", nil)
+		if task.Env == nil {
+			task.Env = make(map[string]string)
+		}
+		vars, _ := override["vars"].(map[string]interface{})
+		for k, v := range vars {
+			task.Env[k] = v.(string)
+		}
 	}
+	return nil
+}
 
-	// Update jobspec submission data if available.
-	// Safely ignore errors as this is an optional step.
-	sub, _, err := client.Jobs().Submission(*job.ID, int(*job.Version), opts)
-	if err != nil {
-		log.Printf("[WARN] failed to read job submission: %v", err)
-	} else {
-		err := resourceJobReadSubmission(sub, d, meta)
-		if err != nil {
-			log.Printf("[WARN] failed to update job submission: %v", err)
+// applyRestartOverrides sets the restart policy settings of the
+// restart_override blocks in the task groups of the job.
+func applyRestartOverrides(job *api.Job, overrides []interface{}) error {
+	for _, raw := range overrides {
+		override := raw.(map[string]interface{})
+		groupName := override["group"].(string)
+
+		tg := job.LookupTaskGroup(groupName)
+		if tg == nil {
+			return fmt.Errorf("restart_override: group %q not found in job", groupName)
 		}
-	}
 
+		if tg.RestartPolicy == nil {
+			tg.RestartPolicy = &api.RestartPolicy{}
+		}
+		if attempts := override["attempts"].(int); attempts >= 0 {
+			tg.RestartPolicy.Attempts = pointer.Of(attempts)
+		}
+		if interval := override["interval"].(string); interval != "" {
+			d, err := time.ParseDuration(interval)
+			if err != nil {
+				return fmt.Errorf("restart_override: invalid interval for group %q: %s", groupName, err)
+			}
+			tg.RestartPolicy.Interval = pointer.Of(d)
+		}
+		if delay := override["delay"].(string); delay != "" {
+			d, err := time.ParseDuration(delay)
+			if err != nil {
+				return fmt.Errorf("restart_override: invalid delay for group %q: %s", groupName, err)
+			}
+			tg.RestartPolicy.Delay = pointer.Of(d)
+		}
+		if mode := override["mode"].(string); mode != "" {
+			tg.RestartPolicy.Mode = pointer.Of(mode)
+		}
+	}
 	return nil
 }
 
-func resourceJobReadSubmission(sub *api.JobSubmission, d *schema.ResourceData, meta any) error {
-	if sub == nil {
-		return nil
-	}
+// applyRescheduleOverrides sets the reschedule policy settings of the
+// reschedule_override blocks in the task groups of the job.
+func applyRescheduleOverrides(job *api.Job, overrides []interface{}) error {
+	for _, raw := range overrides {
+		override := raw.(map[string]interface{})
+		groupName := override["group"].(string)
 
-	if sub.Source != "" {
-		d.Set("jobspec", sub.Source)
-	}
+		tg := job.LookupTaskGroup(groupName)
+		if tg == nil {
+			return fmt.Errorf("reschedule_override: group %q not found in job", groupName)
+		}
 
-	if sub.Format == "hcl2" {
-		var err error
-		var hcl2Config HCL2JobParserConfig
+		attempts := override["attempts"].(int)
+		unlimited := override["unlimited"].(bool)
+		if unlimited && attempts > 0 {
+			return fmt.Errorf("reschedule_override: attempts and unlimited are ambiguous for group %q, only one can be set", groupName)
+		}
 
-		hcl2, ok := d.GetOk("hcl2")
-		if ok {
-			hcl2Config, err = parseHCL2JobParserConfig(hcl2)
-			if err != nil {
-				return fmt.Errorf("failed to parse HCL2 config: %v", err)
+		if tg.ReschedulePolicy == nil {
+			tg.ReschedulePolicy = &api.ReschedulePolicy{}
+		}
+		policy := tg.ReschedulePolicy
+
+		// Limited attempts must disable unlimited rescheduling, otherwise
+		// the default policy of service jobs makes them ambiguous.
+		switch {
+		case unlimited:
+			policy.Unlimited = pointer.Of(true)
+			policy.Attempts = pointer.Of(0)
+		case attempts >= 0:
+			policy.Unlimited = pointer.Of(false)
+			policy.Attempts = pointer.Of(attempts)
+		}
+
+		for _, field := range []struct {
+			key   string
+			value **time.Duration
+		}{
+			{"interval", &policy.Interval},
+			{"delay", &policy.Delay},
+			{"max_delay", &policy.MaxDelay},
+		} {
+			v := override[field.key].(string)
+			if v == "" {
+				continue
 			}
-		} else {
-			// Use default values if hcl2 is not set.
-			hcl2Config = HCL2JobParserConfig{
-				AllowFS: false,
-				Enabled: true,
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("reschedule_override: invalid %s for group %q: %s", field.key, groupName, err)
 			}
+			*field.value = pointer.Of(d)
+		}
+		if delayFunction := override["delay_function"].(string); delayFunction != "" {
+			policy.DelayFunction = pointer.Of(delayFunction)
 		}
 
-		// Only update hcl2 if there are changes to variables to avoid
-		// unnecessary updates if hcl2 is not set.
-		if !maps.Equal(sub.VariableFlags, hcl2Config.Vars) {
-			hcl2Config.Vars = sub.VariableFlags
-			d.Set("hcl2", flattenHCL2JobParserConfig(hcl2Config))
+		if policy.Delay != nil && policy.MaxDelay != nil && *policy.MaxDelay < *policy.Delay {
+			return fmt.Errorf("reschedule_override: max_delay (%s) is less than delay (%s) for group %q",
+				*policy.MaxDelay, *policy.Delay, groupName)
 		}
 	}
-
 	return nil
 }
 
-func resourceJobCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
-	log.Printf("[DEBUG] resourceJobCustomizeDiff")
-	providerConfig := meta.(ProviderConfig)
-	client := providerConfig.client
+// applyVaultOverrides sets the Vault settings of the vault_override blocks
+// in the tasks of the job, adding a vault block to tasks that don't have one.
+func applyVaultOverrides(job *api.Job, overrides []interface{}) error {
+	for _, raw := range overrides {
+		override := raw.(map[string]interface{})
+		groupName := override["group"].(string)
+		taskName := override["task"].(string)
+
+		tg := job.LookupTaskGroup(groupName)
+		if tg == nil {
+			return fmt.Errorf("vault_override: group %q not found in job", groupName)
+		}
+		var task *api.Task
+		for _, t := range tg.Tasks {
+			if t.Name == taskName {
+				task = t
+				break
+			}
+		}
+		if task == nil {
+			return fmt.Errorf("vault_override: task %q not found in group %q", taskName, groupName)
+		}
 
-	if !d.NewValueKnown("jobspec") {
-		d.SetNewComputed("name")
-		d.SetNewComputed("modify_index")
-		d.SetNewComputed("namespace")
-		d.SetNewComputed("type")
-		d.SetNewComputed("region")
-		d.SetNewComputed("datacenters")
-		d.SetNewComputed("allocation_ids")
-		d.SetNewComputed("task_groups")
-		d.SetNewComputed("deployment_id")
-		d.SetNewComputed("deployment_status")
-		d.SetNewComputed("status")
+		if task.Vault == nil {
+			task.Vault = &api.Vault{}
+		}
+		if cluster := override["cluster"].(string); cluster != "" {
+			task.Vault.Cluster = cluster
+		}
+		if role := override["role"].(string); role != "" {
+			task.Vault.Role = role
+		}
+		if namespace := override["namespace"].(string); namespace != "" {
+			task.Vault.Namespace = pointer.Of(namespace)
+		}
+		if policies, _ := override["policies"].([]interface{}); len(policies) > 0 {
+			task.Vault.Policies = make([]string, 0, len(policies))
+			for _, p := range policies {
+				task.Vault.Policies = append(task.Vault.Policies, p.(string))
+			}
+		}
+	}
+	return nil
+}
+
+// validateJobConsulPartitions checks that the Consul admin partitions used by
+// the job are allowed by the provider configuration.
+func validateJobConsulPartitions(job *api.Job, providerConfig ProviderConfig) error {
+	if len(providerConfig.allowedConsulPartitions) == 0 {
 		return nil
 	}
 
-	if d.Get("status").(string) == "dead" && d.Get("rerun_if_dead").(bool) {
-		d.SetNewComputed("status")
+	validate := func(c *api.Consul) error {
+		if c == nil || c.Partition == "" || slices.Contains(providerConfig.allowedConsulPartitions, c.Partition) {
+			return nil
+		}
+		return fmt.Errorf("consul partition %q is not allowed, must be one of: %s",
+			c.Partition, strings.Join(providerConfig.allowedConsulPartitions, ", "))
+	}
+
+	for _, tg := range job.TaskGroups {
+		if err := validate(tg.Consul); err != nil {
+			return fmt.Errorf("invalid group %q: %s", *tg.Name, err)
+		}
+		for _, task := range tg.Tasks {
+			if err := validate(task.Consul); err != nil {
+				return fmt.Errorf("invalid task %q in group %q: %s", task.Name, *tg.Name, err)
+			}
+		}
 	}
+	return nil
+}
 
-	oldSpecRaw, newSpecRaw := d.GetChange("jobspec")
-
-	if jobspecEqual("jobspec", oldSpecRaw.(string), newSpecRaw.(string), d) {
-		// nothing to do!
-		return nil
+// validateJobTaskResources checks that no task sets both cpu and cores, which
+// Nomad only rejects when the job is registered.
+func validateJobTaskResources(job *api.Job) error {
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			r := task.Resources
+			if r == nil || r.CPU == nil || r.Cores == nil || *r.CPU == 0 || *r.Cores == 0 {
+				continue
+			}
+			return fmt.Errorf("invalid task %q in group %q: only one of cpu or cores can be set in resources",
+				task.Name, *tg.Name)
+		}
 	}
+	return nil
+}
 
-	// Read job parsing config.
-	jobParserConfig, err := parseJobParserConfig(d)
-	if err != nil {
-		return err
-	}
+func parseJSONJobspec(raw string) (*api.Job, error) {
+	// `nomad job run -output` returns a jobspec with a "Job" root, so
+	// partially parse the input JSON to detect if we have this root.
+	var root map[string]json.RawMessage
 
-	// Use consul token declared on resource, if present.
-	consulToken := d.Get("consul_token").(string)
-	if consulToken == "" {
-		consulToken = *providerConfig.consulToken
+	err := json.Unmarshal([]byte(raw), &root)
+	if err != nil {
+		return nil, err
 	}
 
-	// Use vault token declared on resource, if present.
-	vaultToken := d.Get("vault_token").(string)
-	if vaultToken == "" {
-		vaultToken = *providerConfig.vaultToken
+	jobBytes, ok := root["Job"]
+	if !ok {
+		// Parse the input as is if there's no "Job" root.
+		jobBytes = []byte(raw)
 	}
 
-	// Parse jobspec
-	// Catch syntax errors client-side during plan
-	job, err := parseJobspec(newSpecRaw.(string), jobParserConfig, &vaultToken, &consulToken)
+	// Parse actual job.
+	var job api.Job
+	err = json.Unmarshal(jobBytes, &job)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	defaultNamespace := "default"
-	if job.Namespace == nil || *job.Namespace == "" {
-		job.Namespace = &defaultNamespace
+	return &job, nil
+}
+
+func parseHCL2Jobspec(raw string, config HCL2JobParserConfig) (*api.Job, error) {
+	argVars := []string{}
+	for k, v := range config.Vars {
+		argVars = append(argVars, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	resp, _, err := client.Jobs().PlanOpts(job, &api.PlanOptions{
-		Diff:           false,
-		PolicyOverride: d.Get("policy_override").(bool),
-	}, &api.WriteOptions{
-		Namespace: *job.Namespace,
+	// Variables passed as arguments take precedence over the ones defined in
+	// variable files.
+	return jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+		Path:     "",
+		Body:     []byte(raw),
+		AllowFS:  config.AllowFS,
+		ArgVars:  argVars,
+		VarFiles: config.VarFiles,
+		Strict:   true,
 	})
-	if err != nil {
-		log.Printf("[WARN] failed to validate Nomad plan: %s", err)
+}
+
+// readHCL2VarFiles returns the concatenated content of the HCL2 variable
+// files, as submitted to Nomad along with the jobspec.
+func readHCL2VarFiles(paths []string) (string, error) {
+	var content strings.Builder
+	for _, file := range paths {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("error reading HCL2 variable file: %v", err)
+		}
+		content.Write(b)
+		content.WriteString("\n")
 	}
+	return content.String(), nil
+}
 
-	// If we were able to successfully plan then we can safely populate our
-	// diff with new values based on the job object we got from parsing,
-	// causing the Terraform diff to correctly reflect the planned changes
-	// to the subset of job attributes we include in our schema.
+// hcl2VarFilesDigest returns the hex encoded SHA-256 digest of the content of
+// the HCL2 variable files, or an empty string if there is no content.
+func hcl2VarFilesDigest(content string) string {
+	if content == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
 
-	d.SetNew("name", job.ID)
-	d.SetNew("type", job.Type)
-	d.SetNew("region", job.Region)
-	d.SetNew("datacenters", job.Datacenters)
-	d.SetNew("status", job.Status)
+func jobTaskGroupsRaw(tgs []*api.TaskGroup) []interface{} {
+	ret := make([]interface{}, 0, len(tgs))
 
-	// If the identity has changed and the config asks us to deregister on identity
-	// change then the id field "forces new resource".
-	if d.Get("namespace").(string) != *job.Namespace {
-		log.Printf("[DEBUG] namespace change forces new resource")
-		d.SetNew("namespace", job.Namespace)
-		d.ForceNew("namespace")
-	} else if d.Id() != *job.ID {
-		if d.Get("deregister_on_id_change").(bool) {
-			log.Printf("[DEBUG] name change forces new resource because deregister_on_id_change is set")
-			d.ForceNew("id")
-			d.ForceNew("name")
+	for _, tg := range tgs {
+		tgM := make(map[string]interface{})
+
+		if tg.Name != nil {
+			tgM["name"] = *tg.Name
 		} else {
-			log.Printf("[DEBUG] allowing name change as update because deregister_on_id_change is not set")
+			tgM["name"] = ""
+		}
+		if tg.Count != nil {
+			tgM["count"] = *tg.Count
+		} else {
+			tgM["count"] = 1
+		}
+		if tg.Meta != nil {
+			tgM["meta"] = tg.Meta
+		} else {
+			tgM["meta"] = make(map[string]interface{})
 		}
-	} else {
-		d.SetNew("namespace", job.Namespace)
 
-		// If the identity (namespace+name) _isn't_ changing, then we require consistency of the
-		// job modify index to ensure that the "old" part of our diff
-		// will show what Nomad currently knows.
-		wantModifyIndexStr := d.Get("modify_index").(string)
-		wantModifyIndex, err := strconv.ParseUint(wantModifyIndexStr, 10, 64)
-		if err != nil {
-			// should never happen, because we always write with FormatUint
-			// in Read above.
-			return fmt.Errorf("invalid modify_index in state: %s", err)
+		tasksI := make([]interface{}, 0, len(tg.Tasks))
+		for _, task := range tg.Tasks {
+			taskM := make(map[string]interface{})
+
+			taskM["name"] = task.Name
+			taskM["driver"] = task.Driver
+			if task.Meta != nil {
+				taskM["meta"] = task.Meta
+			} else {
+				taskM["meta"] = make(map[string]interface{})
+			}
+			if task.Env != nil {
+				taskM["env"] = task.Env
+			} else {
+				taskM["env"] = make(map[string]interface{})
+			}
+
+			volumeMountsI := make([]interface{}, 0, len(task.VolumeMounts))
+			for _, vm := range task.VolumeMounts {
+				volumeMountM := make(map[string]interface{})
+
+				volumeMountM["volume"] = vm.Volume
+				volumeMountM["destination"] = vm.Destination
+				volumeMountM["read_only"] = vm.ReadOnly
+
+				volumeMountsI = append(volumeMountsI, volumeMountM)
+			}
+			taskM["volume_mounts"] = volumeMountsI
+			taskM["csi_plugin"] = jobTaskCSIPluginRaw(task.CSIPluginConfig)
+			taskM["lifecycle"] = jobTaskLifecycleRaw(task.Lifecycle)
+			taskM["vault"] = jobTaskVaultRaw(task.Vault)
+			taskM["template"] = jobTaskTemplatesRaw(task.Templates)
+			taskM["artifact"] = jobTaskArtifactsRaw(task.Artifacts)
+			// Canonicalized tasks have the restart policy of their group
+			// merged with their own, like Nomad does.
+			taskM["restart_policy"] = jobRestartPolicyRaw(task.RestartPolicy)
+			taskM["service"] = jobServicesRaw(task.Services)
+			taskM["logs"] = jobTaskLogsRaw(task.LogConfig)
+			taskM["schedule"] = jobTaskScheduleRaw(task.Schedule)
+
+			taskM["kill_timeout"] = durationRaw(task.KillTimeout)
+			taskM["kill_signal"] = task.KillSignal
+			taskM["shutdown_delay"] = task.ShutdownDelay.String()
+			tasksI = append(tasksI, taskM)
 		}
+		tgM["task"] = tasksI
 
-		if resp != nil && resp.JobModifyIndex != wantModifyIndex {
-			// Should rarely happen, but might happen if there was a concurrent
-			// other process writing to Nomad since our Read call.
-			return fmt.Errorf("job modify index has changed since last refresh")
+		volumesI := make([]interface{}, 0, len(tg.Volumes))
+		for _, v := range tg.Volumes {
+			volumeM := make(map[string]interface{})
+
+			volumeM["name"] = v.Name
+			volumeM["type"] = v.Type
+			volumeM["read_only"] = v.ReadOnly
+			volumeM["source"] = v.Source
+			volumeM["per_alloc"] = v.PerAlloc
+			volumeM["access_mode"] = v.AccessMode
+			volumeM["attachment_mode"] = v.AttachmentMode
+
+			volumesI = append(volumesI, volumeM)
 		}
-	}
+		sort.Slice(volumesI, func(i, j int) bool {
+			return volumesI[i].(map[string]interface{})["name"].(string) <
+				volumesI[j].(map[string]interface{})["name"].(string)
+		})
 
-	// We know that applying changes here will change the modify index
-	// _somehow_, but we won't know how much it will increment until
-	// after we complete registration.
-	d.SetNewComputed("modify_index")
-	// similarly, we won't know the allocation ids until after the job registration eval
-	d.SetNewComputed("allocation_ids")
+		tgM["volumes"] = volumesI
 
-	d.SetNew("task_groups", jobTaskGroupsRaw(job.TaskGroups))
+		tgM["shutdown_delay"] = durationRaw(tg.ShutdownDelay)
+		tgM["consul"] = jobConsulRaw(tg.Consul)
+		tgM["ephemeral_disk"] = jobEphemeralDiskRaw(tg.EphemeralDisk)
+		tgM["restart_policy"] = jobRestartPolicyRaw(tg.RestartPolicy)
+		tgM["network"] = jobNetworksRaw(tg.Networks)
+		tgM["service"] = jobServicesRaw(tg.Services)
+		tgM["reschedule_policy"] = jobReschedulePolicyRaw(tg.ReschedulePolicy)
+		ret = append(ret, tgM)
+	}
 
-	return nil
+	return ret
 }
 
-func parseJobParserConfig(d ResourceFieldGetter) (JobParserConfig, error) {
-	config := JobParserConfig{}
+func jobNetworksRaw(networks []*api.NetworkResource) []interface{} {
+	ret := make([]interface{}, 0, len(networks))
+	for _, n := range networks {
+		// Static ports are stored as reserved ports, and the others as
+		// dynamic ports, but both are declared with port blocks.
+		ports := make([]interface{}, 0, len(n.ReservedPorts)+len(n.DynamicPorts))
+		for _, p := range append(slices.Clone(n.ReservedPorts), n.DynamicPorts...) {
+			// Nomad stores the defaults, so use them for jobs not registered yet.
+			hostNetwork := p.HostNetwork
+			if hostNetwork == "" {
+				hostNetwork = "default"
+			}
+			ports = append(ports, map[string]interface{}{
+				"label":        p.Label,
+				"static":       p.Value,
+				"to":           p.To,
+				"host_network": hostNetwork,
+			})
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			return ports[i].(map[string]interface{})["label"].(string) <
+				ports[j].(map[string]interface{})["label"].(string)
+		})
 
-	// Read JSON parser configuration.
-	jsonEnabled := d.Get("json").(bool)
-	config.JSON = JSONJobParserConfig{
-		Enabled: jsonEnabled,
+		mode := n.Mode
+		if mode == "" {
+			mode = "host"
+		}
+		// CNI networks are selected with a mode of cni/<network>.
+		var cniNetwork string
+		if name, ok := strings.CutPrefix(mode, "cni/"); ok {
+			cniNetwork = name
+		}
+		ret = append(ret, map[string]interface{}{
+			"mode":        mode,
+			"cni_network": cniNetwork,
+			"port":        ports,
+		})
 	}
+	return ret
+}
 
-	// Read HCL1 parser configuration.
-	hclEnabled := d.Get("hcl1").(bool)
-	config.HCL1 = HCL1JobParserConfig{
-		Enabled: hclEnabled,
+func jobConsulRaw(c *api.Consul) []interface{} {
+	if c == nil {
+		return []interface{}{}
 	}
 
-	// Read HCL2 parser configuration.
-	hcl2Config, err := parseHCL2JobParserConfig(d.Get("hcl2"))
-	if err != nil {
-		return config, err
+	return []interface{}{map[string]interface{}{
+		"namespace": c.Namespace,
+		"cluster":   c.Cluster,
+		"partition": c.Partition,
+	}}
+}
+
+func jobRestartPolicyRaw(p *api.RestartPolicy) []interface{} {
+	if p == nil {
+		return []interface{}{}
 	}
-	config.HCL2 = hcl2Config
 
-	// JSON and HCL1 parsing are conflicting options.
-	if config.JSON.Enabled && config.HCL1.Enabled {
-		return config, fmt.Errorf("invalid combination. json is %t and hcl1 is %t", config.JSON.Enabled, config.HCL1.Enabled)
+	policyM := map[string]interface{}{
+		"attempts": 0,
+		"interval": durationRaw(p.Interval),
+		"delay":    durationRaw(p.Delay),
+		"mode":     "",
+	}
+	if p.Attempts != nil {
+		policyM["attempts"] = *p.Attempts
+	}
+	if p.Mode != nil {
+		policyM["mode"] = *p.Mode
 	}
 
-	return config, nil
+	return []interface{}{policyM}
 }
 
-func parseHCL2JobParserConfig(raw interface{}) (HCL2JobParserConfig, error) {
-	config := HCL2JobParserConfig{}
-
-	// `hcl2` must be a list with only one element.
-	hcl2List, ok := raw.([]interface{})
-	if !ok || len(hcl2List) > 1 {
-		return config, fmt.Errorf("failed to unpack hcl2 configuration block")
+func jobReschedulePolicyRaw(p *api.ReschedulePolicy) []interface{} {
+	if p == nil {
+		return []interface{}{}
 	}
 
-	// If the list is empty, it means we don't have a `hcl2` block.
-	if len(hcl2List) == 0 {
-		return config, nil
+	policyM := map[string]interface{}{
+		"attempts":       0,
+		"interval":       durationRaw(p.Interval),
+		"delay":          durationRaw(p.Delay),
+		"delay_function": "",
+		"max_delay":      durationRaw(p.MaxDelay),
+		"unlimited":      false,
+	}
+	if p.Attempts != nil {
+		policyM["attempts"] = *p.Attempts
+	}
+	if p.DelayFunction != nil {
+		policyM["delay_function"] = *p.DelayFunction
+	}
+	if p.Unlimited != nil {
+		policyM["unlimited"] = *p.Unlimited
 	}
 
-	// The only element in the list must be a map.
-	hcl2Map, ok := hcl2List[0].(map[string]interface{})
-	if !ok {
-		return config, nil
+	return []interface{}{policyM}
+}
+
+func jobEphemeralDiskRaw(e *api.EphemeralDisk) []interface{} {
+	if e == nil {
+		return []interface{}{}
 	}
 
-	// Read map fields into parser config struct.
-	if allowFS, ok := hcl2Map["allow_fs"].(bool); ok {
-		config.AllowFS = allowFS
+	diskM := map[string]interface{}{
+		"size":    0,
+		"migrate": false,
+		"sticky":  false,
 	}
-	if enabled, ok := hcl2Map["enabled"].(bool); ok {
-		config.Enabled = enabled
+	if e.SizeMB != nil {
+		diskM["size"] = *e.SizeMB
 	}
-	if vars, ok := hcl2Map["vars"].(map[string]interface{}); ok {
-		config.Vars = make(map[string]string)
-		for k, v := range vars {
-			config.Vars[k] = v.(string)
-		}
+	if e.Migrate != nil {
+		diskM["migrate"] = *e.Migrate
+	}
+	if e.Sticky != nil {
+		diskM["sticky"] = *e.Sticky
 	}
 
-	return config, nil
+	return []interface{}{diskM}
 }
 
-func flattenHCL2JobParserConfig(c HCL2JobParserConfig) []any {
-	return []any{map[string]any{
-		"allow_fs": c.AllowFS,
-		"enabled":  c.Enabled,
-		"vars":     c.Vars,
+func jobParameterizedRaw(p *api.ParameterizedJobConfig) []interface{} {
+	if p == nil {
+		return []interface{}{}
+	}
+
+	return []interface{}{map[string]interface{}{
+		"payload":       p.Payload,
+		"meta_required": p.MetaRequired,
+		"meta_optional": p.MetaOptional,
 	}}
 }
 
-func parseJobspec(raw string, config JobParserConfig, vaultToken *string, consulToken *string) (*api.Job, error) {
-	var job *api.Job
-	var err error
+// jobSubmitTime returns the submit time of the job in RFC3339 format, or an
+// empty string if it's not known.
+func jobSubmitTime(submitTime *int64) string {
+	if submitTime == nil || *submitTime == 0 {
+		return ""
+	}
+	return time.Unix(0, *submitTime).UTC().Format(time.RFC3339Nano)
+}
 
-	switch {
-	case config.JSON.Enabled:
-		job, err = parseJSONJobspec(raw)
-	case config.HCL1.Enabled:
-		job, err = jobspec.Parse(strings.NewReader(raw))
-	default:
-		job, err = parseHCL2Jobspec(raw, config.HCL2)
+// jobPeriodicNextRun returns the next time after now the periodic job is
+// launched, in its time zone, or an empty string if it's not periodic or
+// disabled.
+func jobPeriodicNextRun(p *api.PeriodicConfig, now time.Time) string {
+	if p == nil || p.SpecType == nil || (p.Enabled != nil && !*p.Enabled) {
+		return ""
+	}
+
+	loc, err := p.GetLocation()
+	if err != nil {
+		log.Printf("[WARN] invalid periodic time zone: %v", err)
+		return ""
 	}
-
+	next, err := p.Next(now.In(loc))
 	if err != nil {
-		return nil, fmt.Errorf("error parsing jobspec: %s", err)
+		log.Printf("[WARN] failed to compute next periodic launch: %v", err)
+		return ""
 	}
-
-	// If job is empty after parsing, the input is not a valid Nomad job.
-	if job == nil || reflect.DeepEqual(job, &api.Job{}) {
-		return nil, fmt.Errorf("error parsing jobspec: input JSON is not a valid Nomad jobspec")
+	if next.IsZero() {
+		return ""
 	}
+	return next.Format(time.RFC3339)
+}
 
-	// Inject the Vault and Consul tokens
-	job.VaultToken = vaultToken
-	job.ConsulToken = consulToken
+// jobPeriodicLastChildStatus returns the status of the child job most
+// recently launched by the periodic job. Dead children are reported as
+// "failed" if any of their allocations failed, or "complete" otherwise.
+func jobPeriodicLastChildStatus(parentID string, children []*api.JobListStub) string {
+	var last *api.JobListStub
+	for _, child := range children {
+		if child.ParentID != parentID {
+			continue
+		}
+		if last == nil || child.SubmitTime > last.SubmitTime {
+			last = child
+		}
+	}
+	if last == nil {
+		return ""
+	}
+	if last.Status != "dead" {
+		return last.Status
+	}
 
-	return job, nil
+	if last.JobSummary != nil {
+		for _, summary := range last.JobSummary.Summary {
+			if summary.Failed > 0 || summary.Lost > 0 {
+				return "failed"
+			}
+		}
+	}
+	return "complete"
 }
 
-func parseJSONJobspec(raw string) (*api.Job, error) {
-	// `nomad job run -output` returns a jobspec with a "Job" root, so
-	// partially parse the input JSON to detect if we have this root.
-	var root map[string]json.RawMessage
-
-	err := json.Unmarshal([]byte(raw), &root)
-	if err != nil {
-		return nil, err
+func jobPeriodicRaw(p *api.PeriodicConfig) []interface{} {
+	if p == nil {
+		return []interface{}{}
 	}
 
-	jobBytes, ok := root["Job"]
-	if !ok {
-		// Parse the input as is if there's no "Job" root.
-		jobBytes = []byte(raw)
+	periodicM := map[string]interface{}{
+		"cron":             "",
+		"prohibit_overlap": false,
+		"timezone":         "",
+		"enabled":          false,
+	}
+	if p.Spec != nil {
+		periodicM["cron"] = *p.Spec
+	}
+	if p.ProhibitOverlap != nil {
+		periodicM["prohibit_overlap"] = *p.ProhibitOverlap
+	}
+	if p.TimeZone != nil {
+		periodicM["timezone"] = *p.TimeZone
+	}
+	if p.Enabled != nil {
+		periodicM["enabled"] = *p.Enabled
 	}
 
-	// Parse actual job.
-	var job api.Job
-	err = json.Unmarshal(jobBytes, &job)
-	if err != nil {
-		return nil, err
+	return []interface{}{periodicM}
+}
+
+func jobTaskCSIPluginRaw(c *api.TaskCSIPluginConfig) []interface{} {
+	if c == nil {
+		return []interface{}{}
 	}
 
-	return &job, nil
+	return []interface{}{map[string]interface{}{
+		"id":                     c.ID,
+		"type":                   string(c.Type),
+		"mount_dir":              c.MountDir,
+		"stage_publish_base_dir": c.StagePublishBaseDir,
+		"health_timeout":         c.HealthTimeout.String(),
+	}}
 }
 
-func parseHCL2Jobspec(raw string, config HCL2JobParserConfig) (*api.Job, error) {
-	argVars := []string{}
-	for k, v := range config.Vars {
-		argVars = append(argVars, fmt.Sprintf("%s=%s", k, v))
+func jobTaskLifecycleRaw(l *api.TaskLifecycle) []interface{} {
+	if l.Empty() {
+		return []interface{}{}
 	}
 
-	return jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
-		Path:    "",
-		Body:    []byte(raw),
-		AllowFS: config.AllowFS,
-		ArgVars: argVars,
-		Strict:  true,
-	})
+	return []interface{}{map[string]interface{}{
+		"hook":    l.Hook,
+		"sidecar": l.Sidecar,
+	}}
 }
 
-func jobTaskGroupsRaw(tgs []*api.TaskGroup) []interface{} {
-	ret := make([]interface{}, 0, len(tgs))
+func jobTaskVaultRaw(v *api.Vault) []interface{} {
+	if v == nil {
+		return []interface{}{}
+	}
 
-	for _, tg := range tgs {
-		tgM := make(map[string]interface{})
+	vaultM := map[string]interface{}{
+		"cluster":   v.Cluster,
+		"role":      v.Role,
+		"policies":  v.Policies,
+		"namespace": "",
+	}
+	if v.Namespace != nil {
+		vaultM["namespace"] = *v.Namespace
+	}
 
-		if tg.Name != nil {
-			tgM["name"] = *tg.Name
-		} else {
-			tgM["name"] = ""
+	return []interface{}{vaultM}
+}
+
+func jobServicesRaw(services []*api.Service) []interface{} {
+	ret := make([]interface{}, 0, len(services))
+	for _, s := range services {
+		taggedAddresses := make(map[string]interface{}, len(s.TaggedAddresses))
+		for k, v := range s.TaggedAddresses {
+			taggedAddresses[k] = v
 		}
-		if tg.Count != nil {
-			tgM["count"] = *tg.Count
-		} else {
-			tgM["count"] = 1
+
+		// Nomad versions without multiple Consul clusters don't store one.
+		cluster := s.Cluster
+		if cluster == "" {
+			cluster = "default"
 		}
-		if tg.Meta != nil {
-			tgM["meta"] = tg.Meta
-		} else {
-			tgM["meta"] = make(map[string]interface{})
+
+		ret = append(ret, map[string]interface{}{
+			"name":             s.Name,
+			"provider":         s.Provider,
+			"port":             s.PortLabel,
+			"cluster":          cluster,
+			"tagged_addresses": taggedAddresses,
+			"on_update":        s.OnUpdate,
+			"check":            jobServiceChecksRaw(s.Checks),
+		})
+	}
+	return ret
+}
+
+func jobServiceChecksRaw(checks []api.ServiceCheck) []interface{} {
+	ret := make([]interface{}, 0, len(checks))
+	for _, c := range checks {
+		// Header values are joined so they fit in a map of strings.
+		header := make(map[string]interface{}, len(c.Header))
+		for k, v := range c.Header {
+			header[k] = strings.Join(v, ",")
 		}
 
-		tasksI := make([]interface{}, 0, len(tg.Tasks))
-		for _, task := range tg.Tasks {
-			taskM := make(map[string]interface{})
+		checkRestart := []interface{}{}
+		if c.CheckRestart != nil {
+			checkRestart = append(checkRestart, map[string]interface{}{
+				"limit":           c.CheckRestart.Limit,
+				"grace":           durationRaw(c.CheckRestart.Grace),
+				"ignore_warnings": c.CheckRestart.IgnoreWarnings,
+			})
+		}
 
-			taskM["name"] = task.Name
-			taskM["driver"] = task.Driver
-			if task.Meta != nil {
-				taskM["meta"] = task.Meta
-			} else {
-				taskM["meta"] = make(map[string]interface{})
-			}
+		ret = append(ret, map[string]interface{}{
+			"name":                     c.Name,
+			"type":                     c.Type,
+			"command":                  c.Command,
+			"args":                     c.Args,
+			"path":                     c.Path,
+			"protocol":                 c.Protocol,
+			"port":                     c.PortLabel,
+			"expose":                   c.Expose,
+			"address_mode":             c.AddressMode,
+			"interval":                 c.Interval.String(),
+			"timeout":                  c.Timeout.String(),
+			"initial_status":           c.InitialStatus,
+			"tls_server_name":          c.TLSServerName,
+			"tls_skip_verify":          c.TLSSkipVerify,
+			"method":                   c.Method,
+			"body":                     c.Body,
+			"header":                   header,
+			"grpc_service":             c.GRPCService,
+			"grpc_use_tls":             c.GRPCUseTLS,
+			"task":                     c.TaskName,
+			"success_before_passing":   c.SuccessBeforePassing,
+			"failures_before_critical": c.FailuresBeforeCritical,
+			"failures_before_warning":  c.FailuresBeforeWarning,
+			"on_update":                c.OnUpdate,
+			"check_restart":            checkRestart,
+		})
+	}
+	return ret
+}
 
-			volumeMountsI := make([]interface{}, 0, len(task.VolumeMounts))
-			for _, vm := range task.VolumeMounts {
-				volumeMountM := make(map[string]interface{})
+func jobTaskLogsRaw(l *api.LogConfig) []interface{} {
+	if l == nil {
+		return nil
+	}
 
-				volumeMountM["volume"] = vm.Volume
-				volumeMountM["destination"] = vm.Destination
-				volumeMountM["read_only"] = vm.ReadOnly
+	logsM := map[string]interface{}{
+		"max_files":     0,
+		"max_file_size": 0,
+		"disabled":      false,
+	}
+	if l.MaxFiles != nil {
+		logsM["max_files"] = *l.MaxFiles
+	}
+	if l.MaxFileSizeMB != nil {
+		logsM["max_file_size"] = *l.MaxFileSizeMB
+	}
+	switch {
+	case l.Disabled != nil:
+		logsM["disabled"] = *l.Disabled
+	case l.Enabled != nil:
+		// Jobs registered before Nomad 1.5.4 may only set enabled.
+		logsM["disabled"] = !*l.Enabled
+	}
+	return []interface{}{logsM}
+}
 
-				volumeMountsI = append(volumeMountsI, volumeMountM)
-			}
-			taskM["volume_mounts"] = volumeMountsI
+func jobTaskTemplatesRaw(templates []*api.Template) []interface{} {
+	ret := make([]interface{}, 0, len(templates))
+	for _, t := range templates {
+		// The owner of rendered files defaults to the task user, shown as -1.
+		templateM := map[string]interface{}{
+			"destination": "",
+			"vault_grace": durationRaw(t.VaultGrace),
+			"perms":       "",
+			"uid":         -1,
+			"gid":         -1,
+			"wait":        []interface{}{},
+		}
+		if t.DestPath != nil {
+			templateM["destination"] = *t.DestPath
+		}
+		if t.Perms != nil {
+			templateM["perms"] = *t.Perms
+		}
+		if t.Uid != nil {
+			templateM["uid"] = *t.Uid
+		}
+		if t.Gid != nil {
+			templateM["gid"] = *t.Gid
+		}
+		if t.Wait != nil {
+			templateM["wait"] = []interface{}{map[string]interface{}{
+				"min": durationRaw(t.Wait.Min),
+				"max": durationRaw(t.Wait.Max),
+			}}
+		}
+		ret = append(ret, templateM)
+	}
+	return ret
+}
 
-			tasksI = append(tasksI, taskM)
+func jobTaskArtifactsRaw(artifacts []*api.TaskArtifact) []interface{} {
+	ret := make([]interface{}, 0, len(artifacts))
+	for _, a := range artifacts {
+		options := make(map[string]interface{}, len(a.GetterOptions))
+		for k, v := range a.GetterOptions {
+			options[k] = v
+		}
+		headers := make(map[string]interface{}, len(a.GetterHeaders))
+		for k, v := range a.GetterHeaders {
+			headers[k] = v
 		}
-		tgM["task"] = tasksI
 
-		volumesI := make([]interface{}, 0, len(tg.Volumes))
-		for _, v := range tg.Volumes {
-			volumeM := make(map[string]interface{})
+		artifactM := map[string]interface{}{
+			"source":      "",
+			"destination": "",
+			"mode":        "",
+			"insecure":    false,
+			"options":     options,
+			"headers":     headers,
+		}
+		if a.GetterSource != nil {
+			artifactM["source"] = *a.GetterSource
+		}
+		if a.RelativeDest != nil {
+			artifactM["destination"] = *a.RelativeDest
+		}
+		if a.GetterMode != nil {
+			artifactM["mode"] = *a.GetterMode
+		}
+		if a.GetterInsecure != nil {
+			artifactM["insecure"] = *a.GetterInsecure
+		}
+		ret = append(ret, artifactM)
+	}
+	return ret
+}
 
-			volumeM["name"] = v.Name
-			volumeM["type"] = v.Type
-			volumeM["read_only"] = v.ReadOnly
-			volumeM["source"] = v.Source
+func jobTaskScheduleRaw(s *api.TaskSchedule) []interface{} {
+	if s == nil {
+		return []interface{}{}
+	}
 
-			volumesI = append(volumesI, volumeM)
-		}
-		sort.Slice(volumesI, func(i, j int) bool {
-			return volumesI[i].(map[string]interface{})["name"].(string) <
-				volumesI[j].(map[string]interface{})["name"].(string)
+	cronI := make([]interface{}, 0, 1)
+	if s.Cron != nil {
+		cronI = append(cronI, map[string]interface{}{
+			"start":    s.Cron.Start,
+			"end":      s.Cron.End,
+			"timezone": s.Cron.Timezone,
 		})
+	}
 
-		tgM["volumes"] = volumesI
+	return []interface{}{map[string]interface{}{
+		"cron": cronI,
+	}}
+}
 
-		ret = append(ret, tgM)
+// durationRaw returns the string representation of d, or an empty string if
+// d is not set.
+func durationRaw(d *time.Duration) string {
+	if d == nil {
+		return ""
 	}
+	return d.String()
+}
 
-	return ret
+// validateDuration is a schema.SchemaValidateFunc for attributes that must be
+// a valid Go duration string, such as "30s" or "5m".
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+
+	if _, err := time.ParseDuration(v); err != nil {
+		return nil, []error{fmt.Errorf("%q is not a valid duration: %s", k, err)}
+	}
+	return nil, nil
 }
 
 // jobspecDiffSuppress is the DiffSuppressFunc used by the schema to
@@ -1107,9 +5792,296 @@ func jobspecEqual(k, old, new string, d ResourceFieldGetter) bool {
 	}
 
 	// Init
+	normalizeDisconnectStrategies(oldJob)
+	normalizeDisconnectStrategies(newJob)
 	oldJob.Canonicalize()
 	newJob.Canonicalize()
+	normalizeUpdateStrategies(oldJob)
+	normalizeUpdateStrategies(newJob)
+	normalizeSystemJobStrategies(oldJob)
+	normalizeSystemJobStrategies(newJob)
+	normalizeAffinitiesAndSpreads(oldJob)
+	normalizeAffinitiesAndSpreads(newJob)
+	normalizeScalingPolicies(oldJob)
+	normalizeScalingPolicies(newJob)
 
 	// Check for jobspec equality
 	return reflect.DeepEqual(oldJob, newJob)
 }
+
+// canonicalJobJSON returns a canonicalized copy of the job encoded as JSON.
+// The Consul and Vault tokens used to register the job are removed.
+func canonicalJobJSON(job *api.Job) (string, error) {
+	buf, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("error encoding job: %s", err)
+	}
+	var copied api.Job
+	if err := json.Unmarshal(buf, &copied); err != nil {
+		return "", fmt.Errorf("error encoding job: %s", err)
+	}
+
+	copied.Canonicalize()
+	copied.ConsulToken = nil
+	copied.VaultToken = nil
+
+	buf, err = json.MarshalIndent(copied, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding job: %s", err)
+	}
+	return string(buf), nil
+}
+
+// normalizeScalingPolicies normalizes the opaque policy documents of the
+// scaling policies of the job, so equivalent documents compare as equal.
+func normalizeScalingPolicies(job *api.Job) {
+	for _, tg := range job.TaskGroups {
+		if tg.Scaling != nil {
+			tg.Scaling.Policy = normalizeScalingPolicy(tg.Scaling.Policy)
+		}
+		for _, task := range tg.Tasks {
+			for _, p := range task.ScalingPolicies {
+				p.Policy = normalizeScalingPolicy(p.Policy)
+			}
+		}
+	}
+}
+
+// normalizeScalingPolicy returns the policy document with the types used by
+// encoding/json, so values parsed from HCL and JSON jobspecs are the same, and
+// with lists of blocks, such as the autoscaler check blocks, sorted, since
+// their order is not significant. Lists of values keep their order.
+func normalizeScalingPolicy(policy map[string]interface{}) map[string]interface{} {
+	if policy == nil {
+		return nil
+	}
+
+	buf, err := json.Marshal(policy)
+	if err != nil {
+		log.Printf("[WARN] failed to normalize scaling policy: %s", err)
+		return policy
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(buf, &normalized); err != nil {
+		log.Printf("[WARN] failed to normalize scaling policy: %s", err)
+		return policy
+	}
+	return sortPolicyBlocks(normalized).(map[string]interface{})
+}
+
+// sortPolicyBlocks sorts the lists of objects in v by their JSON encoding.
+func sortPolicyBlocks(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = sortPolicyBlocks(e)
+		}
+	case []interface{}:
+		blocks := true
+		for i, e := range v {
+			v[i] = sortPolicyBlocks(e)
+			if _, ok := e.(map[string]interface{}); !ok {
+				blocks = false
+			}
+		}
+		if blocks {
+			sort.SliceStable(v, func(i, j int) bool {
+				a, _ := json.Marshal(v[i])
+				b, _ := json.Marshal(v[j])
+				return string(a) < string(b)
+			})
+		}
+	}
+	return v
+}
+
+// normalizeDisconnectStrategies moves the deprecated group
+// stop_after_client_disconnect into the disconnect block, so switching between
+// the two forms doesn't cause a diff. It must be called before the job is
+// canonicalized.
+func normalizeDisconnectStrategies(job *api.Job) {
+	for _, tg := range job.TaskGroups {
+		if tg.StopAfterClientDisconnect == nil {
+			continue
+		}
+
+		if tg.Disconnect == nil {
+			tg.Disconnect = &api.DisconnectStrategy{}
+		}
+		if tg.Disconnect.StopOnClientAfter == nil {
+			tg.Disconnect.StopOnClientAfter = tg.StopAfterClientDisconnect
+		}
+		tg.StopAfterClientDisconnect = nil
+	}
+}
+
+// stopAfterClientDisconnectRe matches the deprecated group
+// stop_after_client_disconnect attribute in HCL jobspecs.
+var stopAfterClientDisconnectRe = regexp.MustCompile(`(?m)^\s*stop_after_client_disconnect\s*=`)
+
+// jobspecDeprecationWarnings warns about deprecated attributes used in the
+// jobspec. The jobspec is fully parsed and validated when planning, this
+// only looks for the deprecated attributes.
+func jobspecDeprecationWarnings(i interface{}, path cty.Path) diag.Diagnostics {
+	raw, ok := i.(string)
+	if !ok {
+		return nil
+	}
+
+	var deprecated bool
+	if job, err := parseJSONJobspec(raw); err == nil {
+		for _, tg := range job.TaskGroups {
+			if tg.StopAfterClientDisconnect != nil {
+				deprecated = true
+			}
+		}
+	} else {
+		deprecated = stopAfterClientDisconnectRe.MatchString(raw)
+	}
+
+	if !deprecated {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity:      diag.Warning,
+		Summary:       "Deprecated stop_after_client_disconnect",
+		Detail:        "The group stop_after_client_disconnect attribute is deprecated since Nomad 1.8. Use the stop_on_client_after attribute of the disconnect block instead.",
+		AttributePath: path,
+	}}
+}
+
+// jobspecWarnings returns the warnings about the jobspec, so they are
+// reported while planning. The checks that need the job are skipped if the
+// jobspec can't be parsed without the configuration of the resource.
+func jobspecWarnings(i interface{}, path cty.Path) diag.Diagnostics {
+	diags := jobspecDeprecationWarnings(i, path)
+
+	raw, ok := i.(string)
+	if !ok {
+		return diags
+	}
+	job, ok := parseJobspecForWarnings(raw)
+	if !ok {
+		return diags
+	}
+	for _, warning := range jobspecStaticWarnings(job) {
+		warning.AttributePath = path
+		diags = append(diags, warning)
+	}
+	return diags
+}
+
+// parseJobspecForWarnings parses the jobspec without the parser options and
+// HCL2 variables of the resource, which validation functions don't have
+// access to. It returns false if the jobspec can't be parsed this way, for
+// example because it uses variables without a default value.
+func parseJobspecForWarnings(raw string) (*api.Job, bool) {
+	var job *api.Job
+	var err error
+	if json.Valid([]byte(raw)) {
+		job, err = parseJSONJobspec(raw)
+	} else {
+		job, err = parseHCL2Jobspec(raw, HCL2JobParserConfig{})
+	}
+	if err != nil || job == nil || job.ID == nil {
+		return nil, false
+	}
+	for _, tg := range job.TaskGroups {
+		if tg == nil || tg.Name == nil {
+			return nil, false
+		}
+	}
+	return job, true
+}
+
+// jobspecStaticWarnings returns the warnings that only depend on the
+// jobspec. job must not be canonicalized.
+func jobspecStaticWarnings(job *api.Job) diag.Diagnostics {
+	diags := jobUpdateStrategyWarnings(job)
+	return append(diags, jobServicePortWarnings(job)...)
+}
+
+// normalizeSystemJobStrategies removes the strategies of a canonicalized
+// system or sysbatch job that Nomad ignores for these job types, so changing
+// them doesn't cause a diff. System jobs are never rescheduled or migrated,
+// system jobs only honor the max_parallel and stagger update parameters, and
+// sysbatch jobs aren't deployed at all.
+func normalizeSystemJobStrategies(job *api.Job) {
+	if job.Type == nil || (*job.Type != api.JobTypeSystem && *job.Type != api.JobTypeSysbatch) {
+		return
+	}
+
+	job.Reschedule = nil
+	job.Migrate = nil
+	for _, tg := range job.TaskGroups {
+		tg.ReschedulePolicy = nil
+		tg.Migrate = nil
+
+		if tg.Update == nil {
+			continue
+		}
+		if *job.Type == api.JobTypeSysbatch {
+			tg.Update = nil
+			continue
+		}
+		tg.Update = &api.UpdateStrategy{
+			MaxParallel: tg.Update.MaxParallel,
+			Stagger:     tg.Update.Stagger,
+		}
+	}
+}
+
+// normalizeAffinitiesAndSpreads fills in the defaults Nomad uses for the
+// affinities and spreads of a canonicalized job and sorts the spread targets,
+// so equivalent placement preferences compare as equal. Canonicalize already
+// sets the default weights.
+func normalizeAffinitiesAndSpreads(job *api.Job) {
+	normalizeAffinities(job.Affinities)
+	normalizeSpreads(job.Spreads)
+	for _, tg := range job.TaskGroups {
+		normalizeAffinities(tg.Affinities)
+		normalizeSpreads(tg.Spreads)
+		for _, task := range tg.Tasks {
+			normalizeAffinities(task.Affinities)
+		}
+	}
+}
+
+func normalizeAffinities(affinities []*api.Affinity) {
+	for _, a := range affinities {
+		// Nomad uses the equality operator when none is set.
+		if a.Operand == "" {
+			a.Operand = "="
+		}
+	}
+}
+
+func normalizeSpreads(spreads []*api.Spread) {
+	for _, s := range spreads {
+		// Spread targets are matched by value, their order doesn't matter.
+		sort.SliceStable(s.SpreadTarget, func(i, j int) bool {
+			return s.SpreadTarget[i].Value < s.SpreadTarget[j].Value
+		})
+	}
+}
+
+// normalizeUpdateStrategies removes differences in the update strategies of a
+// canonicalized job that don't change how it is deployed, so moving an update
+// block between the job and its groups or toggling auto_promote on a group
+// without canaries doesn't cause a diff.
+func normalizeUpdateStrategies(job *api.Job) {
+	// Canonicalize merges the job update strategy into each group, so the
+	// group strategies are the only ones that matter.
+	job.Update = nil
+
+	for _, tg := range job.TaskGroups {
+		if tg.Update == nil {
+			continue
+		}
+
+		// auto_promote only applies to deployments with canaries.
+		if tg.Update.Canary == nil || *tg.Update.Canary == 0 {
+			tg.Update.AutoPromote = pointer.Of(false)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+// defaultAutopilotConfiguration is the Autopilot configuration of a new Nomad
+// cluster, which is restored when the resource is destroyed.
+var defaultAutopilotConfiguration = api.AutopilotConfiguration{
+	CleanupDeadServers:      true,
+	LastContactThreshold:    200 * time.Millisecond,
+	MaxTrailingLogs:         250,
+	ServerStabilizationTime: 10 * time.Second,
+}
+
+func resourceOperatorAutopilotConfig() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceOperatorAutopilotConfigCreate,
+		Update: resourceOperatorAutopilotConfigCreate,
+		Delete: resourceOperatorAutopilotConfigDelete,
+		Read:   resourceOperatorAutopilotConfigRead,
+
+		Schema: map[string]*schema.Schema{
+			"cleanup_dead_servers": {
+				Description: "Specifies automatic removal of dead server nodes periodically and whenever a new server is added to the cluster.",
+				Type:        schema.TypeBool,
+				Default:     defaultAutopilotConfiguration.CleanupDeadServers,
+				Optional:    true,
+			},
+			"last_contact_threshold": {
+				Description:      "Specifies the maximum amount of time a server can go without contact from the leader before being considered unhealthy.",
+				Type:             schema.TypeString,
+				Default:          defaultAutopilotConfiguration.LastContactThreshold.String(),
+				Optional:         true,
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"max_trailing_logs": {
+				Description: "Specifies the maximum number of log entries that a server can trail the leader by before being considered unhealthy.",
+				Type:        schema.TypeInt,
+				Default:     int(defaultAutopilotConfiguration.MaxTrailingLogs),
+				Optional:    true,
+			},
+			"server_stabilization_time": {
+				Description:      "Specifies the minimum amount of time a server must be stable in the 'healthy' state before being added to the cluster.",
+				Type:             schema.TypeString,
+				Default:          defaultAutopilotConfiguration.ServerStabilizationTime.String(),
+				Optional:         true,
+				ValidateFunc:     validateDuration,
+				DiffSuppressFunc: durationDiffSuppress,
+			},
+			"enable_redundancy_zones": {
+				Description: "(Enterprise-only) Specifies whether to enable redundancy zones.",
+				Type:        schema.TypeBool,
+				Default:     false,
+				Optional:    true,
+			},
+			"disable_upgrade_migration": {
+				Description: "(Enterprise-only) Disables Autopilot's upgrade migration strategy.",
+				Type:        schema.TypeBool,
+				Default:     false,
+				Optional:    true,
+			},
+			"enable_custom_upgrades": {
+				Description: "(Enterprise-only) Specifies whether to enable using custom upgrade versions when performing migrations.",
+				Type:        schema.TypeBool,
+				Default:     false,
+				Optional:    true,
+			},
+			"modify_index": {
+				Description: "The index of the last update to the Autopilot configuration.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func resourceOperatorAutopilotConfigCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	// Validation has already been performed, so the durations are well
+	// formed.
+	lastContactThreshold, _ := time.ParseDuration(d.Get("last_contact_threshold").(string))
+	serverStabilizationTime, _ := time.ParseDuration(d.Get("server_stabilization_time").(string))
+
+	config := api.AutopilotConfiguration{
+		CleanupDeadServers:      d.Get("cleanup_dead_servers").(bool),
+		LastContactThreshold:    lastContactThreshold,
+		MaxTrailingLogs:         uint64(d.Get("max_trailing_logs").(int)),
+		ServerStabilizationTime: serverStabilizationTime,
+		EnableRedundancyZones:   d.Get("enable_redundancy_zones").(bool),
+		DisableUpgradeMigration: d.Get("disable_upgrade_migration").(bool),
+		EnableCustomUpgrades:    d.Get("enable_custom_upgrades").(bool),
+	}
+
+	// Updates are checked against the index read into state, so changes made
+	// outside of Terraform are not overwritten without being refreshed first.
+	if d.IsNewResource() {
+		current, _, err := client.Operator().AutopilotGetConfiguration(nil)
+		if err != nil {
+			return fmt.Errorf("error reading autopilot configuration: %s", err.Error())
+		}
+		config.ModifyIndex = current.ModifyIndex
+	} else {
+		index, err := strconv.ParseUint(d.Get("modify_index").(string), 10, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing autopilot configuration modify index: %s", err.Error())
+		}
+		config.ModifyIndex = index
+	}
+
+	log.Printf("[DEBUG] Upserting autopilot configuration")
+	if err := autopilotCASConfiguration(client, &config); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Upserted autopilot configuration")
+
+	return resourceOperatorAutopilotConfigRead(d, meta)
+}
+
+// resourceOperatorAutopilotConfigDelete resets the Autopilot configuration to
+// the Nomad defaults.
+func resourceOperatorAutopilotConfigDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	current, _, err := client.Operator().AutopilotGetConfiguration(nil)
+	if err != nil {
+		return fmt.Errorf("error reading autopilot configuration: %s", err.Error())
+	}
+
+	config := defaultAutopilotConfiguration
+	config.ModifyIndex = current.ModifyIndex
+
+	log.Printf("[DEBUG] Resetting autopilot configuration")
+	if err := autopilotCASConfiguration(client, &config); err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Reset autopilot configuration")
+
+	return nil
+}
+
+func resourceOperatorAutopilotConfigRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	// The autopilot config doesn't have a UUID, so the resource uses the
+	// agent region. Grab this so we can set it later.
+	reg, err := client.Agent().Region()
+	if err != nil {
+		return fmt.Errorf("error getting region: %s", err.Error())
+	}
+
+	log.Printf("[DEBUG] Reading autopilot configuration")
+	config, _, err := client.Operator().AutopilotGetConfiguration(nil)
+	if err != nil {
+		return fmt.Errorf("error reading autopilot configuration: %s", err.Error())
+	}
+	log.Printf("[DEBUG] Read autopilot configuration")
+
+	d.SetId(fmt.Sprintf("nomad-autopilot-configuration-%s", reg))
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("cleanup_dead_servers", config.CleanupDeadServers)
+	sw.Set("last_contact_threshold", config.LastContactThreshold.String())
+	sw.Set("max_trailing_logs", int(config.MaxTrailingLogs))
+	sw.Set("server_stabilization_time", config.ServerStabilizationTime.String())
+	sw.Set("enable_redundancy_zones", config.EnableRedundancyZones)
+	sw.Set("disable_upgrade_migration", config.DisableUpgradeMigration)
+	sw.Set("enable_custom_upgrades", config.EnableCustomUpgrades)
+	sw.Set("modify_index", strconv.FormatUint(config.ModifyIndex, 10))
+	return sw.Error()
+}
+
+func autopilotCASConfiguration(client *api.Client, config *api.AutopilotConfiguration) error {
+	ok, _, err := client.Operator().AutopilotCASConfiguration(config, nil)
+	if err != nil {
+		return fmt.Errorf("error upserting autopilot configuration: %s", err.Error())
+	}
+	if !ok {
+		return errors.New("error upserting autopilot configuration: the configuration was modified concurrently, refresh and try again")
+	}
+	return nil
+}
+
+// durationDiffSuppress suppresses diffs between equivalent durations, such as
+// "1m" and "1m0s".
+func durationDiffSuppress(_, old, new string, _ *schema.ResourceData) bool {
+	oldDuration, err := time.ParseDuration(old)
+	if err != nil {
+		return false
+	}
+	newDuration, err := time.ParseDuration(new)
+	if err != nil {
+		return false
+	}
+	return oldDuration == newDuration
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestOperatorAutopilotConfig_basic(t *testing.T) {
+	resourceName := "nomad_operator_autopilot_config.config"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testProviders,
+		CheckDestroy: testOperatorAutopilotConfig_checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNomadOperatorAutopilotConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cleanup_dead_servers", "false"),
+					resource.TestCheckResourceAttr(resourceName, "last_contact_threshold", "500ms"),
+					resource.TestCheckResourceAttr(resourceName, "max_trailing_logs", "500"),
+					resource.TestCheckResourceAttr(resourceName, "server_stabilization_time", "30s"),
+					resource.TestCheckResourceAttrSet(resourceName, "modify_index"),
+				),
+			},
+			{
+				Config: testAccNomadOperatorAutopilotConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "cleanup_dead_servers", "true"),
+					resource.TestCheckResourceAttr(resourceName, "last_contact_threshold", "1s"),
+					resource.TestCheckResourceAttr(resourceName, "max_trailing_logs", "250"),
+					resource.TestCheckResourceAttr(resourceName, "server_stabilization_time", "1m0s"),
+				),
+			},
+			{
+				// Equivalent durations must not cause a diff.
+				Config:   testAccNomadOperatorAutopilotConfigUpdate,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testOperatorAutopilotConfig_checkDestroy(_ *terraform.State) error {
+	client := testProvider.Meta().(ProviderConfig).client
+
+	config, _, err := client.Operator().AutopilotGetConfiguration(nil)
+	if err != nil {
+		return fmt.Errorf("error reading autopilot configuration: %s", err)
+	}
+
+	if config.CleanupDeadServers != defaultAutopilotConfiguration.CleanupDeadServers ||
+		config.LastContactThreshold != defaultAutopilotConfiguration.LastContactThreshold ||
+		config.MaxTrailingLogs != defaultAutopilotConfiguration.MaxTrailingLogs ||
+		config.ServerStabilizationTime != defaultAutopilotConfiguration.ServerStabilizationTime {
+		return fmt.Errorf("autopilot configuration was not reset to defaults: %#v", config)
+	}
+	return nil
+}
+
+const testAccNomadOperatorAutopilotConfig = `
+resource "nomad_operator_autopilot_config" "config" {
+	cleanup_dead_servers      = false
+	last_contact_threshold    = "500ms"
+	max_trailing_logs         = 500
+	server_stabilization_time = "30s"
+}
+`
+
+const testAccNomadOperatorAutopilotConfigUpdate = `
+resource "nomad_operator_autopilot_config" "config" {
+	last_contact_threshold    = "1s"
+	server_stabilization_time = "1m"
+}
+`
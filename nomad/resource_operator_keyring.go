@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func resourceOperatorKeyring() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceOperatorKeyringCreate,
+		Update: resourceOperatorKeyringUpdate,
+		Delete: resourceOperatorKeyringDelete,
+		Read:   resourceOperatorKeyringRead,
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Description:  "The base64 encoded gossip encryption key to install.",
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Sensitive:    true,
+				ValidateFunc: validateGossipKey,
+			},
+			"primary": {
+				Description: "If true, the key is made the primary key used to encrypt gossip messages.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"keys": {
+				Description: "The gossip encryption keys installed in the cluster.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceOperatorKeyringCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+	key := d.Get("key").(string)
+
+	log.Printf("[DEBUG] Installing gossip encryption key")
+	if _, err := client.Agent().InstallKey(key); err != nil {
+		return fmt.Errorf("error installing gossip encryption key: %s", err.Error())
+	}
+	log.Printf("[DEBUG] Installed gossip encryption key")
+
+	// The key is sensitive, so use its hash to identify the resource.
+	d.SetId(gossipKeyID(key))
+
+	if d.Get("primary").(bool) {
+		if err := useGossipKey(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return resourceOperatorKeyringRead(d, meta)
+}
+
+func resourceOperatorKeyringUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Unsetting primary is a no-op, another key must be made primary
+	// instead.
+	if d.HasChange("primary") && d.Get("primary").(bool) {
+		if err := useGossipKey(d, meta); err != nil {
+			return err
+		}
+	}
+
+	return resourceOperatorKeyringRead(d, meta)
+}
+
+func resourceOperatorKeyringDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+	key := d.Get("key").(string)
+
+	log.Printf("[DEBUG] Removing gossip encryption key")
+	_, err := client.Agent().RemoveKey(key)
+	if err != nil {
+		// The primary key can't be removed until another key is made
+		// primary, so leave it installed rather than failing the destroy.
+		if strings.Contains(err.Error(), "primary key") {
+			log.Printf("[WARN] Gossip encryption key is the primary key and was not removed from the keyring")
+			return nil
+		}
+		return fmt.Errorf("error removing gossip encryption key: %s", err.Error())
+	}
+	log.Printf("[DEBUG] Removed gossip encryption key")
+
+	return nil
+}
+
+func resourceOperatorKeyringRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+	key := d.Get("key").(string)
+
+	log.Printf("[DEBUG] Reading gossip encryption keys")
+	resp, err := client.Agent().ListKeys()
+	if err != nil {
+		return fmt.Errorf("error reading gossip encryption keys: %s", err.Error())
+	}
+	log.Printf("[DEBUG] Read gossip encryption keys")
+
+	if _, ok := resp.Keys[key]; !ok {
+		log.Printf("[DEBUG] Gossip encryption key is no longer installed")
+		d.SetId("")
+		return nil
+	}
+
+	keys := make([]string, 0, len(resp.Keys))
+	for k := range resp.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("keys", keys)
+	return sw.Error()
+}
+
+func useGossipKey(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Making gossip encryption key primary")
+	if _, err := client.Agent().UseKey(d.Get("key").(string)); err != nil {
+		return fmt.Errorf("error making gossip encryption key primary: %s", err.Error())
+	}
+	log.Printf("[DEBUG] Made gossip encryption key primary")
+	return nil
+}
+
+// gossipKeyID returns a non-sensitive identifier for a gossip encryption key.
+func gossipKeyID(key string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+}
+
+// validateGossipKey checks that the key is base64 encoded and has a length
+// supported by Serf.
+func validateGossipKey(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s must be base64 encoded: %s", k, err)}
+	}
+
+	switch len(decoded) {
+	case 16, 24, 32:
+		return nil, nil
+	default:
+		return nil, []error{fmt.Errorf("%s must be 16, 24 or 32 bytes long once decoded, got %d", k, len(decoded))}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testOperatorKeyringKey = "HS5lJ+XuTlYKWaeGYyG+/A=="
+
+func TestOperatorKeyring_basic(t *testing.T) {
+	resourceName := "nomad_operator_keyring.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testCheckGossipEncryptionEnabled(t)
+		},
+		Providers:    testProviders,
+		CheckDestroy: testOperatorKeyring_checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNomadOperatorKeyring,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "id", gossipKeyID(testOperatorKeyringKey)),
+					resource.TestCheckTypeSetElemAttr(resourceName, "keys.*", testOperatorKeyringKey),
+				),
+			},
+		},
+	})
+}
+
+func TestValidateGossipKey(t *testing.T) {
+	testCases := []struct {
+		key     string
+		isValid bool
+	}{
+		{key: testOperatorKeyringKey, isValid: true},
+		{key: "not base64!", isValid: false},
+		{key: "c2hvcnQ=", isValid: false},
+	}
+
+	for _, tc := range testCases {
+		_, errs := validateGossipKey(tc.key, "key")
+		if tc.isValid && len(errs) > 0 {
+			t.Errorf("expected key %q to be valid, got %v", tc.key, errs)
+		}
+		if !tc.isValid && len(errs) == 0 {
+			t.Errorf("expected key %q to be invalid", tc.key)
+		}
+	}
+}
+
+func testOperatorKeyring_checkDestroy(_ *terraform.State) error {
+	client := testProvider.Meta().(ProviderConfig).client
+
+	resp, err := client.Agent().ListKeys()
+	if err != nil {
+		return fmt.Errorf("error reading gossip encryption keys: %s", err)
+	}
+
+	if _, ok := resp.Keys[testOperatorKeyringKey]; ok {
+		return fmt.Errorf("gossip encryption key was not removed")
+	}
+	return nil
+}
+
+var testAccNomadOperatorKeyring = fmt.Sprintf(`
+resource "nomad_operator_keyring" "test" {
+	key = %q
+}
+`, testOperatorKeyringKey)
@@ -56,9 +56,12 @@ func resourceSchedulerConfig() *schema.Resource {
 	}
 }
 
+// schedulerConfigCASAttempts is the number of times the scheduler
+// configuration is written before giving up on concurrent changes.
+const schedulerConfigCASAttempts = 5
+
 func resourceSchedulerConfigurationCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(ProviderConfig).client
-	operator := client.Operator()
 
 	config := api.SchedulerConfiguration{
 		SchedulerAlgorithm:            api.SchedulerAlgorithm(d.Get("scheduler_algorithm").(string)),
@@ -90,8 +93,8 @@ func resourceSchedulerConfigurationCreate(d *schema.ResourceData, meta interface
 
 	// Perform the config write.
 	log.Printf("[DEBUG] Upserting Scheduler configuration")
-	if _, _, err := operator.SchedulerSetConfiguration(&config, nil); err != nil {
-		return fmt.Errorf("error upserting scheduler configuration: %s", err.Error())
+	if err := schedulerCASConfiguration(client, config); err != nil {
+		return err
 	}
 	log.Printf("[DEBUG] Upserted scheduler configuration")
 
@@ -151,3 +154,40 @@ func resourceSchedulerConfigurationRead(d *schema.ResourceData, meta interface{}
 	}
 	return d.Set("preemption_config", premptMap)
 }
+
+// schedulerCASConfiguration writes the fields of the scheduler configuration
+// managed by the resource with a check-and-set operation. If the
+// configuration is modified concurrently, the current configuration is read
+// again and the write is retried, up to schedulerConfigCASAttempts times.
+// Fields not managed by the resource keep their current value.
+func schedulerCASConfiguration(client *api.Client, desired api.SchedulerConfiguration) error {
+	operator := client.Operator()
+
+	for attempt := 1; attempt <= schedulerConfigCASAttempts; attempt++ {
+		current, _, err := operator.SchedulerGetConfiguration(nil)
+		if err != nil {
+			return fmt.Errorf("error reading scheduler configuration: %s", err.Error())
+		}
+
+		config := api.SchedulerConfiguration{}
+		if current.SchedulerConfig != nil {
+			config = *current.SchedulerConfig
+		}
+		config.SchedulerAlgorithm = desired.SchedulerAlgorithm
+		config.PreemptionConfig = desired.PreemptionConfig
+		config.MemoryOversubscriptionEnabled = desired.MemoryOversubscriptionEnabled
+
+		resp, _, err := operator.SchedulerCASConfiguration(&config, nil)
+		if err != nil {
+			return fmt.Errorf("error upserting scheduler configuration: %s", err.Error())
+		}
+		if resp.Updated {
+			return nil
+		}
+		log.Printf("[DEBUG] Scheduler configuration was modified concurrently (attempt %d of %d)",
+			attempt, schedulerConfigCASAttempts)
+	}
+
+	return fmt.Errorf("error upserting scheduler configuration: the configuration was modified concurrently %d times",
+		schedulerConfigCASAttempts)
+}
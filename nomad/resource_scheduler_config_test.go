@@ -4,7 +4,11 @@
 package nomad
 
 import (
+	"encoding/json"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	"github.com/hashicorp/nomad/api"
@@ -12,6 +16,90 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+func TestSchedulerCASConfiguration(t *testing.T) {
+	testCases := []struct {
+		name      string
+		conflicts int
+		expectErr bool
+	}{
+		{name: "no conflict", conflicts: 0},
+		{name: "retry after conflict", conflicts: 2},
+		{name: "too many conflicts", conflicts: schedulerConfigCASAttempts, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := api.SchedulerConfiguration{
+				SchedulerAlgorithm:    api.SchedulerAlgorithmBinpack,
+				RejectJobRegistration: true,
+				ModifyIndex:           10,
+			}
+			conflicts := tc.conflicts
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodGet:
+					json.NewEncoder(w).Encode(api.SchedulerConfigurationResponse{
+						SchedulerConfig: &current,
+					})
+				case http.MethodPut:
+					var config api.SchedulerConfiguration
+					if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+						t.Errorf("err: %s", err)
+					}
+					if cas := r.URL.Query().Get("cas"); cas != strconv.FormatUint(current.ModifyIndex, 10) {
+						t.Errorf("unexpected cas index %q", cas)
+					}
+
+					// Simulate a concurrent write between the read and the
+					// check-and-set operation.
+					updated := conflicts == 0
+					if updated {
+						config.ModifyIndex = current.ModifyIndex + 1
+						current = config
+					} else {
+						conflicts--
+					}
+					json.NewEncoder(w).Encode(api.SchedulerSetConfigurationResponse{
+						Updated: updated,
+					})
+				}
+			}))
+			defer srv.Close()
+
+			conf := api.DefaultConfig()
+			conf.Address = srv.URL
+			client, err := api.NewClient(conf)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			err = schedulerCASConfiguration(client, api.SchedulerConfiguration{
+				SchedulerAlgorithm:            api.SchedulerAlgorithmSpread,
+				MemoryOversubscriptionEnabled: true,
+			})
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if current.SchedulerAlgorithm != api.SchedulerAlgorithmSpread {
+				t.Fatalf("expected spread algorithm, got %q", current.SchedulerAlgorithm)
+			}
+			if !current.MemoryOversubscriptionEnabled {
+				t.Fatal("expected memory oversubscription to be enabled")
+			}
+			if !current.RejectJobRegistration {
+				t.Fatal("expected unmanaged fields to be preserved")
+			}
+		})
+	}
+}
+
 func TestSchedulerConfig_basic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -13,6 +13,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// sentinelPolicyScopes are the request types Nomad evaluates Sentinel policies
+// against. Invalid scopes are rejected during plan instead of failing with a
+// server error on apply.
+var sentinelPolicyScopes = []string{
+	"submit-job",
+	"submit-host-volume",
+	"submit-csi-volume",
+}
+
 func resourceSentinelPolicy() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceSentinelPolicyWrite,
@@ -40,10 +49,10 @@ func resourceSentinelPolicy() *schema.Resource {
 			},
 
 			"scope": {
-				Description:  "Specifies the scope for this policy. Only 'submit-job' is currently supported.",
+				Description:  "Specifies the scope for this policy. One of 'submit-job', 'submit-host-volume' or 'submit-csi-volume'.",
 				Required:     true,
 				Type:         schema.TypeString,
-				ValidateFunc: validation.StringInSlice([]string{"submit-job"}, false),
+				ValidateFunc: validation.StringInSlice(sentinelPolicyScopes, false),
 			},
 
 			"enforcement_level": {
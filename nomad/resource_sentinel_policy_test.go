@@ -6,6 +6,7 @@ package nomad
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -123,6 +124,20 @@ all_drivers_exec = rule {
 	})
 }
 
+func TestResourceSentinelPolicy_invalidScope(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceSentinelPolicy_config("invalid-scope", "", `main = rule { true }`, "submit-node", "advisory"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`expected scope to be one of \["submit-job" "submit-host-volume" "submit-csi-volume"\], got submit-node`),
+			},
+		},
+	})
+}
+
 func testResourceSentinelPolicy_config(name, description, sentinelPolicy, scope, enforcementLevel string) string {
 	return fmt.Sprintf(`
 resource "nomad_sentinel_policy" "test" {
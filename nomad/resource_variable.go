@@ -59,6 +59,12 @@ func resourceVariable() *schema.Resource {
 				Required:    true,
 				Sensitive:   true,
 			},
+			"merge": {
+				Description: "If true, the items are merged into the existing variable instead of replacing it, and only these items are removed on destroy",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 		},
 	}
 }
@@ -76,6 +82,16 @@ func resourceVariableWrite(d *schema.ResourceData, meta any) error {
 		variable.Items[name] = value.(string)
 	}
 
+	if d.Get("merge").(bool) {
+		oldItems, _ := d.GetChange("items")
+		if err := resourceVariableMergeWrite(client, variable, oldItems.(map[string]any)); err != nil {
+			return err
+		}
+
+		d.SetId(variable.Path + "@" + variable.Namespace)
+		return resourceVariableRead(d, meta)
+	}
+
 	log.Printf("[DEBUG] Upserting variable %s@%s", variable.Path, variable.Namespace)
 	if _, _, err := client.Variables().Create(variable, nil); err != nil {
 		return fmt.Errorf("error creating variable %s@%s: %s", variable.Path, variable.Namespace, err.Error())
@@ -94,6 +110,20 @@ func resourceVariableDelete(d *schema.ResourceData, meta any) error {
 	path := d.Get("path").(string)
 	ns := d.Get("namespace").(string)
 
+	if d.Get("merge").(bool) {
+		variable := &api.Variable{
+			Namespace: ns,
+			Path:      path,
+			Items:     make(map[string]string),
+		}
+		if err := resourceVariableMergeWrite(client, variable, d.Get("items").(map[string]any)); err != nil {
+			return err
+		}
+
+		d.SetId("")
+		return nil
+	}
+
 	log.Printf("[DEBUG] Deleting variable %q", variableID)
 	if _, err := client.Variables().Delete(path, &api.WriteOptions{Namespace: ns}); err != nil {
 		return fmt.Errorf("error deleting variable %s: %v", variableID, err)
@@ -118,8 +148,74 @@ func resourceVariableRead(d *schema.ResourceData, meta any) error {
 		return fmt.Errorf("error getting information about %s: %v", variableID, err)
 	}
 
+	items := variable.Items
+	if d.Get("merge").(bool) {
+		// Only track the items owned by this resource.
+		items = make(map[string]string)
+		for name := range d.Get("items").(map[string]any) {
+			if value, ok := variable.Items[name]; ok {
+				items[name] = value
+			}
+		}
+	}
+
 	d.SetId(variableID)
-	return d.Set("items", variable.Items)
+	return d.Set("items", items)
+}
+
+// resourceVariableMergeWrite merges the items of variable into the existing
+// variable at the same path, removing the items in ownedItems that are no
+// longer present in variable. The variable is deleted if no items are left
+// and the write is rejected if the variable is modified concurrently.
+func resourceVariableMergeWrite(client *api.Client, variable *api.Variable, ownedItems map[string]any) error {
+	variableID := variable.Path + "@" + variable.Namespace
+
+	current, _, err := client.Variables().Peek(variable.Path, &api.QueryOptions{Namespace: variable.Namespace})
+	if err != nil {
+		return fmt.Errorf("error reading variable %s: %v", variableID, err)
+	}
+
+	items := make(map[string]string)
+	var modifyIndex uint64
+	if current != nil {
+		modifyIndex = current.ModifyIndex
+		for name, value := range current.Items {
+			if _, owned := ownedItems[name]; owned {
+				continue
+			}
+			items[name] = value
+		}
+	}
+	for name, value := range variable.Items {
+		items[name] = value
+	}
+
+	opts := &api.WriteOptions{Namespace: variable.Namespace}
+
+	if len(items) == 0 {
+		if current == nil {
+			return nil
+		}
+
+		log.Printf("[DEBUG] Deleting variable %s since it has no items left", variableID)
+		if _, err := client.Variables().CheckedDelete(variable.Path, modifyIndex, opts); err != nil {
+			return fmt.Errorf("error deleting variable %s: %v", variableID, err)
+		}
+		return nil
+	}
+
+	log.Printf("[DEBUG] Merging items into variable %s", variableID)
+	merged := &api.Variable{
+		Namespace:   variable.Namespace,
+		Path:        variable.Path,
+		Items:       items,
+		ModifyIndex: modifyIndex,
+	}
+	if _, _, err := client.Variables().CheckedUpdate(merged, opts); err != nil {
+		return fmt.Errorf("error merging items into variable %s: %v", variableID, err)
+	}
+
+	return nil
 }
 
 func resourceVariableExists(d *schema.ResourceData, meta any) (bool, error) {
@@ -79,6 +79,83 @@ func TestResourceVariable_namespaceChange(t *testing.T) {
 	})
 }
 
+func TestResourceVariable_merge(t *testing.T) {
+	path := acctest.RandomWithPrefix("tf-nomad-test")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t); testCheckMinVersion(t, "1.4.0") },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceVariable_mergeConfig(path, true),
+				Check: testResourceVariable_itemsCheck(api.DefaultNamespace, path, map[string]string{
+					"first_key":  "first_value",
+					"second_key": "second_value",
+				}),
+			},
+			{
+				Config: testResourceVariable_mergeConfig(path, false),
+				Check: testResourceVariable_itemsCheck(api.DefaultNamespace, path, map[string]string{
+					"first_key": "first_value",
+				}),
+			},
+		},
+
+		CheckDestroy: testResourceVariable_checkDestroy(api.DefaultNamespace, path),
+	})
+}
+
+func testResourceVariable_mergeConfig(path string, withSecond bool) string {
+	config := fmt.Sprintf(`
+resource "nomad_variable" "first" {
+  path  = "%s"
+  merge = true
+
+  items = {
+    first_key = "first_value"
+  }
+}
+`, path)
+
+	if withSecond {
+		config += fmt.Sprintf(`
+resource "nomad_variable" "second" {
+  path  = "%s"
+  merge = true
+
+  items = {
+    second_key = "second_value"
+  }
+
+  depends_on = [nomad_variable.first]
+}
+`, path)
+	}
+
+	return config
+}
+
+func testResourceVariable_itemsCheck(namespace, path string, expected map[string]string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(ProviderConfig).client
+		variable, _, err := client.Variables().Read(path, &api.QueryOptions{Namespace: namespace})
+		if err != nil {
+			return fmt.Errorf("error reading back variable %s@%s: %s", path, namespace, err)
+		}
+
+		if len(variable.Items) != len(expected) {
+			return fmt.Errorf("expected %d items, got %d", len(expected), len(variable.Items))
+		}
+		for k, v := range expected {
+			if variable.Items[k] != v {
+				return fmt.Errorf("expected item %q to be %q, got %q", k, v, variable.Items[k])
+			}
+		}
+
+		return nil
+	}
+}
+
 func testResourceVariable_initialConfig(namespace, path string) string {
 	return fmt.Sprintf(`
 resource "nomad_variable" "test" {
@@ -0,0 +1,3 @@
+datacenters      = ["dc1", "dc2"]
+restart_attempts = 3
+args             = ["20"]